@@ -196,6 +196,26 @@ type Operation struct {
 	Targets      []addrs.Targetable
 	Variables    map[string]UnparsedVariableValue
 
+	// MaxDestroy and MaxChange, if non-zero, cap the number of resource
+	// instances that an auto-approved apply is allowed to destroy or
+	// change (create/update/destroy, combined) respectively. If the plan
+	// exceeds either threshold the operation is aborted before any changes
+	// are made, even though AutoApprove would otherwise skip the
+	// interactive confirmation prompt. They have no effect when
+	// AutoApprove is false, since the interactive prompt already gives the
+	// user a chance to review the plan.
+	MaxDestroy int
+	MaxChange  int
+
+	// ResultFile, if set, is the path to write a machine-readable JSON
+	// artifact describing the outcome of an apply: the per-resource result
+	// (including any error), the final output values, and the new state
+	// serial. Deployment pipelines can consume this instead of parsing
+	// console output to decide whether to proceed with subsequent steps.
+	//
+	// Currently only honored by the local backend's apply operation.
+	ResultFile string
+
 	// Some operations use root module variables only opportunistically or
 	// don't need them at all. If this flag is set, the backend must treat
 	// all variables as optional and provide an unknown value for any required
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/terraform/backend"
+	"github.com/hashicorp/terraform/plans"
 	"github.com/hashicorp/terraform/states"
 	"github.com/hashicorp/terraform/states/statefile"
 	"github.com/hashicorp/terraform/states/statemgr"
@@ -42,12 +44,15 @@ func (b *Local) opApply(
 	// Setup our count hook that keeps track of resource changes
 	countHook := new(CountHook)
 	stateHook := new(StateHook)
+	resultHook := new(ResultHook)
 	if b.ContextOpts == nil {
 		b.ContextOpts = new(terraform.ContextOpts)
 	}
 	old := b.ContextOpts.Hooks
 	defer func() { b.ContextOpts.Hooks = old }()
-	b.ContextOpts.Hooks = append(b.ContextOpts.Hooks, countHook, stateHook)
+	b.ContextOpts.Hooks = append(b.ContextOpts.Hooks, countHook, stateHook, resultHook)
+
+	startedAt := time.Now()
 
 	// Get our context
 	tfCtx, _, opState, contextDiags := b.context(op)
@@ -86,6 +91,16 @@ func (b *Local) opApply(
 		trivialPlan := plan.Changes.Empty()
 		hasUI := op.UIOut != nil && op.UIIn != nil
 		mustConfirm := hasUI && ((op.Destroy && (!op.DestroyForce && !op.AutoApprove)) || (!op.Destroy && !op.AutoApprove && !trivialPlan))
+
+		if op.AutoApprove && (op.MaxDestroy > 0 || op.MaxChange > 0) {
+			if budgetDiags := checkChangeBudget(plan.Changes, op.MaxDestroy, op.MaxChange); budgetDiags.HasErrors() {
+				diags = diags.Append(budgetDiags)
+				runningOp.Result = backend.OperationFailure
+				b.ReportResult(runningOp, diags)
+				return
+			}
+		}
+
 		if mustConfirm {
 			var desc, query string
 			if op.Destroy {
@@ -107,9 +122,23 @@ func (b *Local) opApply(
 			}
 
 			if !trivialPlan {
-				// Display the plan of what we are going to apply/destroy.
-				b.renderPlan(plan, runningOp.State, tfCtx.Schemas())
+				// Show a compact summary grouped by module and action rather
+				// than immediately scrolling the user through the full diff,
+				// which can be thousands of lines for a large plan. The full
+				// diff remains a keystroke away via the "details" prompt
+				// response handled below.
+				b.CLI.Output(b.Colorize().Color("[reset]\n" + formatChangeSummary(plan.Changes)))
+				if risky := riskyReplacements(plan.Changes, riskyResourceTypes()); len(risky) > 0 {
+					b.CLI.Output(b.Colorize().Color("\n[bold][yellow]Warning:[reset] the following changes affect resource types that often hold hard-to-recreate data:\n"))
+					for _, addr := range risky {
+						b.CLI.Output("  " + addr)
+					}
+				}
+				b.CLI.Output(b.Colorize().Color(fmt.Sprintf(
+					"\n[bold]Plan:[reset] %s", changeCountSummary(plan.Changes),
+				)))
 				b.CLI.Output("")
+				desc += "\nType \"details\" to see the full plan before deciding."
 			}
 
 			// We'll show any accumulated warnings before we display the prompt,
@@ -119,15 +148,24 @@ func (b *Local) opApply(
 				diags = nil // reset so we won't show the same diagnostics again later
 			}
 
-			v, err := op.UIIn.Input(stopCtx, &terraform.InputOpts{
-				Id:          "approve",
-				Query:       query,
-				Description: desc,
-			})
-			if err != nil {
-				diags = diags.Append(errwrap.Wrapf("Error asking for approval: {{err}}", err))
-				b.ReportResult(runningOp, diags)
-				return
+			var v string
+			for {
+				var err error
+				v, err = op.UIIn.Input(stopCtx, &terraform.InputOpts{
+					Id:          "approve",
+					Query:       query,
+					Description: desc,
+				})
+				if err != nil {
+					diags = diags.Append(errwrap.Wrapf("Error asking for approval: {{err}}", err))
+					b.ReportResult(runningOp, diags)
+					return
+				}
+				if v != "details" {
+					break
+				}
+				b.renderPlan(plan, runningOp.State, tfCtx.Schemas())
+				b.CLI.Output("")
 			}
 			if v != "yes" {
 				if op.Destroy {
@@ -173,16 +211,20 @@ func (b *Local) opApply(
 		stateFile.State = applyState
 
 		diags = diags.Append(b.backupStateForError(stateFile, err))
+		b.runPostApplyHooks(op, tfCtx, resultHook, startedAt, applyState, opState, err)
 		b.ReportResult(runningOp, diags)
 		return
 	}
 
 	diags = diags.Append(applyDiags)
 	if applyDiags.HasErrors() {
+		b.runPostApplyHooks(op, tfCtx, resultHook, startedAt, applyState, opState, applyDiags.Err())
 		b.ReportResult(runningOp, diags)
 		return
 	}
 
+	b.runPostApplyHooks(op, tfCtx, resultHook, startedAt, applyState, opState, nil)
+
 	// If we've accumulated any warnings along the way then we'll show them
 	// here just before we show the summary and next steps. If we encountered
 	// errors then we would've returned early at some other point above.
@@ -218,6 +260,85 @@ func (b *Local) opApply(
 	}
 }
 
+// checkChangeBudget enforces the -max-destroy and -max-change guardrails on
+// an auto-approved apply, returning error diagnostics if the given planned
+// changes exceed either threshold. A threshold of zero means "no limit".
+//
+// This exists to give CI automation that always passes -auto-approve a
+// last-line defense against an unexpectedly catastrophic plan, since such
+// automation has no human present to notice a plan summary that looks
+// wrong.
+func checkChangeBudget(changes *plans.Changes, maxDestroy, maxChange int) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var destroyCount, changeCount int
+	for _, rc := range changes.Resources {
+		if rc.Action == plans.NoOp {
+			continue
+		}
+		changeCount++
+		if rc.Action == plans.Delete || rc.Action.IsReplace() {
+			destroyCount++
+		}
+	}
+
+	if maxDestroy > 0 && destroyCount > maxDestroy {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Change budget exceeded",
+			fmt.Sprintf(
+				"The plan would destroy %d resource instance(s), which exceeds the -max-destroy limit of %d. Aborting before making any changes.\n\nRe-run without -auto-approve to review the plan interactively, or raise -max-destroy if this many destroys are expected.",
+				destroyCount, maxDestroy,
+			),
+		))
+	}
+
+	if maxChange > 0 && changeCount > maxChange {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Change budget exceeded",
+			fmt.Sprintf(
+				"The plan would make %d change(s) (create, update, or destroy), which exceeds the -max-change limit of %d. Aborting before making any changes.\n\nRe-run without -auto-approve to review the plan interactively, or raise -max-change if this many changes are expected.",
+				changeCount, maxChange,
+			),
+		))
+	}
+
+	return diags
+}
+
+// runPostApplyHooks builds the machine-readable ApplyResult for the
+// just-completed apply operation, writes it to op.ResultFile if requested,
+// and runs any on_apply_success/on_apply_failure command configured in a
+// "hooks" block in the root module, logging (rather than failing the
+// operation) if either step goes wrong. applyErr should be the error that
+// caused the apply to fail, or nil if it succeeded.
+func (b *Local) runPostApplyHooks(op *backend.Operation, tfCtx *terraform.Context, hook *ResultHook, startedAt time.Time, state *states.State, opState statemgr.Full, applyErr error) {
+	needResult := op.ResultFile != ""
+	if tfCtx != nil {
+		if config := tfCtx.Config(); config != nil && config.Module.Hooks != nil {
+			needResult = true
+		}
+	}
+	if !needResult {
+		return
+	}
+
+	result, err := buildApplyResult(hook, startedAt, state, opState, applyErr)
+	if err != nil {
+		log.Printf("[WARN] backend/local: failed to build apply result artifact: %s", err)
+		return
+	}
+
+	if op.ResultFile != "" {
+		if err := writeApplyResult(op.ResultFile, result); err != nil {
+			log.Printf("[WARN] backend/local: failed to write apply result artifact to %s: %s", op.ResultFile, err)
+		}
+	}
+
+	b.runApplyHooks(tfCtx, result, applyErr)
+}
+
 // backupStateForError is called in a scenario where we're unable to persist the
 // state for some reason, and will attempt to save a backup copy of the state
 // to local disk to help the user recover. This is a "last ditch effort" sort
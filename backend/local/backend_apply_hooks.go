@@ -0,0 +1,69 @@
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// runApplyHooks invokes the on_apply_success or on_apply_failure command
+// configured in a "hooks" block inside the root module's "terraform" block,
+// if any, passing the given ApplyResult as JSON on the command's standard
+// input. This lets a configuration send its own notifications or invalidate
+// its own caches after an apply, instead of relying on an external wrapper
+// script to poll for the outcome.
+//
+// A failure to run the hook command is logged but does not itself affect
+// the outcome of the apply, since the apply has already completed by the
+// time hooks run.
+func (b *Local) runApplyHooks(tfCtx *terraform.Context, result *ApplyResult, applyErr error) {
+	if tfCtx == nil {
+		return
+	}
+	config := tfCtx.Config()
+	if config == nil || config.Module.Hooks == nil {
+		return
+	}
+
+	command := config.Module.Hooks.OnApplySuccess
+	if applyErr != nil {
+		command = config.Module.Hooks.OnApplyFailure
+	}
+	if command == "" {
+		return
+	}
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("[WARN] backend/local: failed to marshal apply result for hooks: %s", err)
+		return
+	}
+
+	if err := runApplyHookCommand(command, payload); err != nil {
+		log.Printf("[WARN] backend/local: apply hook command failed: %s", err)
+	}
+}
+
+// runApplyHookCommand runs command in a shell, writing stdin to its standard
+// input and its combined output to the process's own stderr, mirroring how
+// the local-exec provisioner shells out to a command.
+func runApplyHookCommand(command string, stdin []byte) error {
+	var cmdargs []string
+	if runtime.GOOS == "windows" {
+		cmdargs = []string{"cmd", "/C"}
+	} else {
+		cmdargs = []string{"/bin/sh", "-c"}
+	}
+	cmdargs = append(cmdargs, command)
+
+	cmd := exec.Command(cmdargs[0], cmdargs[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
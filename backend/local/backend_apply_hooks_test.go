@@ -0,0 +1,142 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/configs"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func testApplyHooksContext(t *testing.T, hooks *configs.Hooks) *terraform.Context {
+	t.Helper()
+
+	config := configs.NewEmptyConfig()
+	config.Module.Hooks = hooks
+
+	ctx, diags := terraform.NewContext(&terraform.ContextOpts{
+		Config: config,
+	})
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	return ctx
+}
+
+func TestLocal_runApplyHooks(t *testing.T) {
+	b, cleanup := TestLocal(t)
+	defer cleanup()
+
+	successFile, err := ioutil.TempFile("", "tf-apply-hook-success")
+	if err != nil {
+		t.Fatal(err)
+	}
+	successFile.Close()
+	defer os.Remove(successFile.Name())
+
+	failureFile, err := ioutil.TempFile("", "tf-apply-hook-failure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	failureFile.Close()
+	defer os.Remove(failureFile.Name())
+
+	hooks := &configs.Hooks{
+		OnApplySuccess: "cat > " + successFile.Name(),
+		OnApplyFailure: "cat > " + failureFile.Name(),
+	}
+	tfCtx := testApplyHooksContext(t, hooks)
+
+	t.Run("success", func(t *testing.T) {
+		os.Remove(successFile.Name())
+		os.Remove(failureFile.Name())
+
+		b.runApplyHooks(tfCtx, &ApplyResult{Success: true}, nil)
+
+		if _, err := os.Stat(successFile.Name()); err != nil {
+			t.Fatalf("expected on_apply_success command to run: %s", err)
+		}
+		if _, err := os.Stat(failureFile.Name()); err == nil {
+			t.Fatal("did not expect on_apply_failure command to run")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		os.Remove(successFile.Name())
+		os.Remove(failureFile.Name())
+
+		b.runApplyHooks(tfCtx, &ApplyResult{Success: false}, errors.New("apply failed"))
+
+		if _, err := os.Stat(failureFile.Name()); err != nil {
+			t.Fatalf("expected on_apply_failure command to run: %s", err)
+		}
+		if _, err := os.Stat(successFile.Name()); err == nil {
+			t.Fatal("did not expect on_apply_success command to run")
+		}
+	})
+}
+
+func TestLocal_runApplyHooks_nilContext(t *testing.T) {
+	b, cleanup := TestLocal(t)
+	defer cleanup()
+
+	// Must not panic when there is no terraform.Context to consult, such as
+	// when the apply failed before a context could be built.
+	b.runApplyHooks(nil, &ApplyResult{Success: false}, errors.New("apply failed"))
+}
+
+func TestLocal_runApplyHooks_noHooksBlock(t *testing.T) {
+	b, cleanup := TestLocal(t)
+	defer cleanup()
+
+	tfCtx := testApplyHooksContext(t, nil)
+
+	// Must not panic, and must not attempt to run any command, when the
+	// root module has no "hooks" block at all.
+	b.runApplyHooks(tfCtx, &ApplyResult{Success: true}, nil)
+}
+
+func TestLocal_runApplyHooks_commandUnset(t *testing.T) {
+	b, cleanup := TestLocal(t)
+	defer cleanup()
+
+	// Only on_apply_failure is configured, so a successful apply must not
+	// run any command.
+	tfCtx := testApplyHooksContext(t, &configs.Hooks{OnApplyFailure: "true"})
+	b.runApplyHooks(tfCtx, &ApplyResult{Success: true}, nil)
+}
+
+func TestRunApplyHookCommand(t *testing.T) {
+	out, err := ioutil.TempFile("", "tf-apply-hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+
+	stdin, err := json.Marshal(&ApplyResult{Success: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runApplyHookCommand("cat > "+out.Name(), stdin); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	got, err := ioutil.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(stdin) {
+		t.Fatalf("wrong content\ngot:  %s\nwant: %s", got, stdin)
+	}
+}
+
+func TestRunApplyHookCommand_empty(t *testing.T) {
+	if err := runApplyHookCommand("true", nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
@@ -0,0 +1,113 @@
+package local
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/states/statemgr"
+)
+
+// ApplyResultFormatVersion represents the version of the apply result
+// artifact format produced by writeApplyResult, and will be incremented for
+// any change to this format that requires changes to a consuming parser.
+const ApplyResultFormatVersion = "0.1"
+
+// ApplyResult is the top-level representation of the machine-readable
+// artifact written after an apply when -result-file is given, so that
+// deployment pipelines can gate subsequent steps without parsing console
+// output.
+type ApplyResult struct {
+	FormatVersion string                       `json:"format_version"`
+	Success       bool                         `json:"success"`
+	StartedAt     time.Time                    `json:"started_at"`
+	CompletedAt   time.Time                    `json:"completed_at"`
+	StateSerial   *uint64                      `json:"state_serial,omitempty"`
+	Resources     []ApplyResultResource        `json:"resources"`
+	Outputs       map[string]ApplyResultOutput `json:"outputs,omitempty"`
+	Error         string                       `json:"error,omitempty"`
+}
+
+// ApplyResultResource describes the outcome of a single resource instance
+// change.
+type ApplyResultResource struct {
+	Address     string    `json:"address"`
+	Action      string    `json:"action"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ApplyResultOutput describes the final value of a root module output
+// value.
+type ApplyResultOutput struct {
+	Value     json.RawMessage `json:"value,omitempty"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+// buildApplyResult assembles an ApplyResult from the outcome of a single
+// apply operation, for serialization to the path given in -result-file.
+func buildApplyResult(hook *ResultHook, startedAt time.Time, state *states.State, opState statemgr.Full, applyErr error) (*ApplyResult, error) {
+	result := &ApplyResult{
+		FormatVersion: ApplyResultFormatVersion,
+		Success:       applyErr == nil,
+		StartedAt:     startedAt,
+		CompletedAt:   time.Now(),
+	}
+	if applyErr != nil {
+		result.Error = applyErr.Error()
+	}
+
+	if mr, ok := opState.(statemgr.PersistentMeta); ok {
+		serial := mr.StateSnapshotMeta().Serial
+		result.StateSerial = &serial
+	}
+
+	for _, entry := range hook.Resources {
+		res := ApplyResultResource{
+			Address:     entry.Addr.String(),
+			Action:      entry.Action.String(),
+			Success:     entry.Err == nil,
+			StartedAt:   entry.StartedAt,
+			CompletedAt: entry.EndedAt,
+		}
+		if entry.Err != nil {
+			res.Error = entry.Err.Error()
+		}
+		result.Resources = append(result.Resources, res)
+	}
+
+	if state != nil {
+		outputs := state.RootModule().OutputValues
+		if len(outputs) > 0 {
+			result.Outputs = make(map[string]ApplyResultOutput, len(outputs))
+			for name, ov := range outputs {
+				raw, err := ctyjson.Marshal(ov.Value, ov.Value.Type())
+				if err != nil {
+					return nil, err
+				}
+				result.Outputs[name] = ApplyResultOutput{
+					Value:     raw,
+					Sensitive: ov.Sensitive,
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// writeApplyResult serializes the given ApplyResult as JSON to the given
+// path.
+func writeApplyResult(path string, result *ApplyResult) error {
+	src, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	src = append(src, '\n')
+	return ioutil.WriteFile(path, src, 0644)
+}
@@ -0,0 +1,45 @@
+package local
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/plans"
+)
+
+func TestBuildApplyResult_resourceAction(t *testing.T) {
+	hook := &ResultHook{
+		Resources: []ResultHookEntry{
+			{
+				Addr:      mustResourceInstanceAddr("test_instance.foo"),
+				Action:    plans.Create,
+				StartedAt: time.Now(),
+				EndedAt:   time.Now(),
+			},
+			{
+				Addr:      mustResourceInstanceAddr("test_instance.bar"),
+				Action:    plans.Delete,
+				Err:       errors.New("boom"),
+				StartedAt: time.Now(),
+				EndedAt:   time.Now(),
+			},
+		},
+	}
+
+	result, err := buildApplyResult(hook, time.Now(), nil, nil, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got, want := len(result.Resources), 2; got != want {
+		t.Fatalf("wrong number of resources: got %d, want %d", got, want)
+	}
+
+	if got, want := result.Resources[0].Action, "Create"; got != want {
+		t.Errorf("wrong action for foo: got %q, want %q", got, want)
+	}
+	if got, want := result.Resources[1].Action, "Delete"; got != want {
+		t.Errorf("wrong action for bar: got %q, want %q", got, want)
+	}
+}
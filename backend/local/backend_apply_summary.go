@@ -0,0 +1,158 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/plans"
+)
+
+// RiskyResourceTypesEnvVar is the name of an environment variable that can
+// be set to a comma-separated list of resource types that opApply should
+// call out specifically when they appear in a destroy or replace action, in
+// addition to the built-in list in defaultRiskyResourceTypes. This is
+// intended for resource types that tend to hold data that's expensive or
+// impossible to recreate, such as databases and object storage.
+const RiskyResourceTypesEnvVar = "TF_RISKY_RESOURCE_TYPES"
+
+// defaultRiskyResourceTypes returns the built-in set of resource types that
+// opApply treats as "data-bearing" for the purposes of the apply
+// confirmation warning, before considering RiskyResourceTypesEnvVar.
+func defaultRiskyResourceTypes() map[string]bool {
+	return map[string]bool{
+		"aws_db_instance":              true,
+		"aws_rds_cluster":              true,
+		"aws_dynamodb_table":           true,
+		"aws_s3_bucket":                true,
+		"aws_elasticsearch_domain":     true,
+		"google_sql_database_instance": true,
+		"google_storage_bucket":        true,
+		"azurerm_sql_database":         true,
+		"azurerm_storage_account":      true,
+	}
+}
+
+// riskyResourceTypes builds the effective set of resource types to flag,
+// combining defaultRiskyResourceTypes with any additional types named in
+// RiskyResourceTypesEnvVar.
+func riskyResourceTypes() map[string]bool {
+	types := defaultRiskyResourceTypes()
+	for _, t := range strings.Split(os.Getenv(RiskyResourceTypesEnvVar), ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			types[t] = true
+		}
+	}
+	return types
+}
+
+// formatChangeSummary renders a compact summary of the given planned
+// changes, grouped by module and, within each module, by action, showing
+// only the action and address of each change rather than the full
+// attribute-level diff. This is intended to be
+// shown ahead of the interactive apply confirmation prompt, with the full
+// diff available on request by typing "details".
+func formatChangeSummary(changes *plans.Changes) string {
+	type entry struct {
+		action plans.Action
+		addr   string
+	}
+	byModule := map[string][]entry{}
+
+	for _, rc := range changes.Resources {
+		if rc.Action == plans.NoOp {
+			continue
+		}
+		if rc.Action == plans.Delete && rc.Addr.Resource.Resource.Mode == addrs.DataResourceMode {
+			// Data source removals are just a bookkeeping detail, not
+			// something the user needs to approve.
+			continue
+		}
+		modKey := rc.Addr.Module.String()
+		if modKey == "" {
+			modKey = "(root module)"
+		}
+		byModule[modKey] = append(byModule[modKey], entry{rc.Action, rc.Addr.String()})
+	}
+
+	if len(byModule) == 0 {
+		return ""
+	}
+
+	modKeys := make([]string, 0, len(byModule))
+	for k := range byModule {
+		modKeys = append(modKeys, k)
+	}
+	sort.Strings(modKeys)
+
+	var buf bytes.Buffer
+	buf.WriteString("Plan summary:\n")
+	for _, modKey := range modKeys {
+		entries := byModule[modKey]
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].action != entries[j].action {
+				return entries[i].action < entries[j].action
+			}
+			return entries[i].addr < entries[j].addr
+		})
+		fmt.Fprintf(&buf, "\n%s:\n", modKey)
+		for _, e := range entries {
+			fmt.Fprintf(&buf, "  %s %s\n", string(e.action), e.addr)
+		}
+	}
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// changeCountSummary renders the standard "N to add, M to change, K to
+// destroy." tally of the given planned changes, simplifying a replace
+// action into both an add and a destroy. This is shown ahead of the apply
+// confirmation prompt whenever the plan has changes, regardless of whether
+// the full plan rendering is also requested.
+func changeCountSummary(changes *plans.Changes) string {
+	counts := map[plans.Action]int{}
+	for _, rc := range changes.Resources {
+		if rc.Action == plans.Delete && rc.Addr.Resource.Resource.Mode == addrs.DataResourceMode {
+			continue
+		}
+		switch rc.Action {
+		case plans.CreateThenDelete, plans.DeleteThenCreate:
+			counts[plans.Create]++
+			counts[plans.Delete]++
+		default:
+			counts[rc.Action]++
+		}
+	}
+
+	return fmt.Sprintf(
+		"%d to add, %d to change, %d to destroy.",
+		counts[plans.Create], counts[plans.Update], counts[plans.Delete],
+	)
+}
+
+// riskyReplacements returns the addresses of any resource instances in the
+// given changes that are being destroyed or replaced and whose resource
+// type is considered "data-bearing" per riskyResourceTypes.
+//
+// Terraform already refuses at plan time to destroy any resource instance
+// whose configuration has prevent_destroy set, so by the time a destroy or
+// replace change reaches apply confirmation it's guaranteed to have
+// prevent_destroy unset (or false), making the resource type alone a
+// sufficient signal for this warning.
+func riskyReplacements(changes *plans.Changes, risky map[string]bool) []string {
+	var flagged []string
+	for _, rc := range changes.Resources {
+		if rc.Action != plans.Delete && !rc.Action.IsReplace() {
+			continue
+		}
+		if risky[rc.Addr.Resource.Resource.Type] {
+			flagged = append(flagged, fmt.Sprintf("%s %s", string(rc.Action), rc.Addr.String()))
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
@@ -0,0 +1,165 @@
+package local
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/plans"
+)
+
+func testChanges(actions ...plans.Action) *plans.Changes {
+	changes := &plans.Changes{}
+	for i, action := range actions {
+		changes.Resources = append(changes.Resources, &plans.ResourceInstanceChangeSrc{
+			Addr: addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: string(rune('a' + i)),
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			ChangeSrc: plans.ChangeSrc{Action: action},
+		})
+	}
+	return changes
+}
+
+func TestFormatChangeSummary(t *testing.T) {
+	tests := map[string]struct {
+		changes *plans.Changes
+		want    string
+	}{
+		"no changes": {
+			testChanges(),
+			"",
+		},
+		"create and delete": {
+			testChanges(plans.Create, plans.Delete),
+			"Plan summary:\n\n(root module):\n  + test_instance.a\n  - test_instance.b",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := formatChangeSummary(test.changes)
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatChangeSummary_dataSourceDelete(t *testing.T) {
+	changes := &plans.Changes{
+		Resources: []*plans.ResourceInstanceChangeSrc{
+			{
+				Addr: addrs.Resource{
+					Mode: addrs.DataResourceMode,
+					Type: "test_data_source",
+					Name: "foo",
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				ChangeSrc: plans.ChangeSrc{Action: plans.Delete},
+			},
+		},
+	}
+
+	got := formatChangeSummary(changes)
+	if got != "" {
+		t.Errorf("expected data source removal to be omitted, got %q", got)
+	}
+}
+
+func TestChangeCountSummary(t *testing.T) {
+	tests := map[string]struct {
+		changes *plans.Changes
+		want    string
+	}{
+		"no changes": {
+			testChanges(),
+			"0 to add, 0 to change, 0 to destroy.",
+		},
+		"create, update, delete": {
+			testChanges(plans.Create, plans.Update, plans.Delete),
+			"1 to add, 1 to change, 1 to destroy.",
+		},
+		"replace counts as both add and destroy": {
+			testChanges(plans.DeleteThenCreate, plans.CreateThenDelete),
+			"2 to add, 0 to change, 2 to destroy.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := changeCountSummary(test.changes)
+			if got != test.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestChangeCountSummary_dataSourceDeleteIgnored(t *testing.T) {
+	changes := &plans.Changes{
+		Resources: []*plans.ResourceInstanceChangeSrc{
+			{
+				Addr: addrs.Resource{
+					Mode: addrs.DataResourceMode,
+					Type: "test_data_source",
+					Name: "foo",
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				ChangeSrc: plans.ChangeSrc{Action: plans.Delete},
+			},
+		},
+	}
+
+	got := changeCountSummary(changes)
+	want := "0 to add, 0 to change, 0 to destroy."
+	if got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestDefaultRiskyResourceTypes(t *testing.T) {
+	types := defaultRiskyResourceTypes()
+	for _, want := range []string{"aws_db_instance", "aws_s3_bucket", "google_sql_database_instance"} {
+		if !types[want] {
+			t.Errorf("expected %s to be in the default risky resource types", want)
+		}
+	}
+	if types["test_instance"] {
+		t.Errorf("did not expect test_instance to be in the default risky resource types")
+	}
+}
+
+func TestRiskyResourceTypes_env(t *testing.T) {
+	old := os.Getenv(RiskyResourceTypesEnvVar)
+	defer os.Setenv(RiskyResourceTypesEnvVar, old)
+
+	os.Setenv(RiskyResourceTypesEnvVar, "test_instance, other_thing")
+	types := riskyResourceTypes()
+	if !types["test_instance"] || !types["other_thing"] {
+		t.Fatalf("expected env-supplied types to be included, got %#v", types)
+	}
+	if !types["aws_db_instance"] {
+		t.Fatalf("expected default types to still be included, got %#v", types)
+	}
+}
+
+func TestRiskyReplacements(t *testing.T) {
+	changes := testChanges(plans.Create, plans.Delete, plans.DeleteThenCreate)
+	risky := map[string]bool{"test_instance": true}
+
+	got := riskyReplacements(changes, risky)
+	want := []string{"- test_instance.b", "∓ test_instance.c"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestRiskyReplacements_notRisky(t *testing.T) {
+	changes := testChanges(plans.Delete)
+	got := riskyReplacements(changes, map[string]bool{})
+	if len(got) != 0 {
+		t.Fatalf("expected no risky replacements, got %#v", got)
+	}
+}
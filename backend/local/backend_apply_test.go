@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform/backend"
 	"github.com/hashicorp/terraform/configs/configschema"
 	"github.com/hashicorp/terraform/internal/initwd"
+	"github.com/hashicorp/terraform/plans"
 	"github.com/hashicorp/terraform/providers"
 	"github.com/hashicorp/terraform/states"
 	"github.com/hashicorp/terraform/states/statemgr"
@@ -64,6 +65,41 @@ test_instance.foo:
 `)
 }
 
+func TestLocal_applyApprovalSummary(t *testing.T) {
+	b, cleanup := TestLocal(t)
+	defer cleanup()
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+	p.ApplyResourceChangeResponse = providers.ApplyResourceChangeResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("yes"),
+		"ami": cty.StringVal("bar"),
+	})}
+
+	b.CLI = new(cli.MockUi)
+
+	op, configCleanup := testOperationApply(t, "./testdata/apply")
+	defer configCleanup()
+	op.UIIn = &terraform.MockUIInput{InputReturnString: "yes"}
+	op.UIOut = b.CLI
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result != backend.OperationSuccess {
+		t.Fatal("operation failed")
+	}
+
+	// The "N to add, M to change, K to destroy" tally must always appear
+	// ahead of the confirmation prompt, regardless of whether the user
+	// goes on to request the full plan details.
+	output := b.CLI.(*cli.MockUi).OutputWriter.String()
+	if !strings.Contains(output, "1 to add, 0 to change, 0 to destroy.") {
+		t.Fatalf("expected plan summary in output:\n%s", output)
+	}
+}
+
 func TestLocal_applyEmptyDir(t *testing.T) {
 	b, cleanup := TestLocal(t)
 	defer cleanup()
@@ -231,6 +267,69 @@ test_instance.foo:
 	`)
 }
 
+func TestCheckChangeBudget(t *testing.T) {
+	tests := map[string]struct {
+		changes               *plans.Changes
+		maxDestroy, maxChange int
+		wantErr               bool
+	}{
+		"no limits set": {
+			testChanges(plans.Create, plans.Update, plans.Delete, plans.Delete, plans.Delete),
+			0, 0,
+			false,
+		},
+		"destroys under limit": {
+			testChanges(plans.Delete, plans.Delete),
+			3, 0,
+			false,
+		},
+		"destroys at limit": {
+			testChanges(plans.Delete, plans.Delete, plans.Delete),
+			3, 0,
+			false,
+		},
+		"destroys over limit": {
+			testChanges(plans.Delete, plans.Delete, plans.Delete, plans.Delete),
+			3, 0,
+			true,
+		},
+		"replace counts toward destroy limit": {
+			testChanges(plans.DeleteThenCreate, plans.DeleteThenCreate),
+			1, 0,
+			true,
+		},
+		"changes under limit": {
+			testChanges(plans.Create, plans.Update),
+			0, 3,
+			false,
+		},
+		"changes at limit": {
+			testChanges(plans.Create, plans.Update, plans.Delete),
+			0, 3,
+			false,
+		},
+		"changes over limit": {
+			testChanges(plans.Create, plans.Update, plans.Delete, plans.Delete),
+			0, 3,
+			true,
+		},
+		"noop changes are not counted": {
+			testChanges(plans.NoOp, plans.NoOp, plans.Create),
+			0, 1,
+			false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := checkChangeBudget(test.changes, test.maxDestroy, test.maxChange)
+			if got := diags.HasErrors(); got != test.wantErr {
+				t.Fatalf("wrong result\ngot:  %v\nwant: %v\ndiags: %s", got, test.wantErr, diags.Err())
+			}
+		})
+	}
+}
+
 type backendWithFailingState struct {
 	Local
 }
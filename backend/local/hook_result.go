@@ -0,0 +1,69 @@
+package local
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/plans"
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// ResultHook is a hook that records the outcome and timing of each resource
+// instance change made during an apply, for later serialization into a
+// machine-readable apply result artifact. See ApplyResult in
+// backend_apply_result.go for the shape of that artifact.
+type ResultHook struct {
+	Resources []ResultHookEntry
+
+	pending map[string]int // addr -> index into Resources, while an apply is in progress
+
+	sync.Mutex
+	terraform.NilHook
+}
+
+// ResultHookEntry describes the outcome of a single resource instance
+// change observed by ResultHook.
+type ResultHookEntry struct {
+	Addr      addrs.AbsResourceInstance
+	Action    plans.Action
+	Err       error
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+var _ terraform.Hook = (*ResultHook)(nil)
+
+func (h *ResultHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.pending == nil {
+		h.pending = make(map[string]int)
+	}
+
+	h.Resources = append(h.Resources, ResultHookEntry{
+		Addr:      addr,
+		Action:    action,
+		StartedAt: time.Now(),
+	})
+	h.pending[addr.String()] = len(h.Resources) - 1
+
+	return terraform.HookActionContinue, nil
+}
+
+func (h *ResultHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	if idx, ok := h.pending[addr.String()]; ok {
+		delete(h.pending, addr.String())
+		h.Resources[idx].EndedAt = time.Now()
+		h.Resources[idx].Err = err
+	}
+
+	return terraform.HookActionContinue, nil
+}
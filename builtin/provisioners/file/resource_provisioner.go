@@ -1,12 +1,20 @@
 package file
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/terraform/communicator"
+	"github.com/hashicorp/terraform/communicator/remote"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/mitchellh/go-homedir"
@@ -31,6 +39,23 @@ func Provisioner() terraform.ResourceProvisioner {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+
+			"checksum": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"excludes": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"sync": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 
 		ApplyFunc:    applyFn,
@@ -57,9 +82,47 @@ func applyFn(ctx context.Context) error {
 		defer os.Remove(src)
 	}
 
-	// Begin the file copy
 	dst := data.Get("destination").(string)
 
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if checksum := data.Get("checksum").(string); checksum != "" {
+		if info.IsDir() {
+			return fmt.Errorf("'checksum' can only be used with a single file 'source' or 'content', not a directory")
+		}
+		if err := verifyChecksum(src, checksum); err != nil {
+			return err
+		}
+	}
+
+	excludes := excludePatterns(data)
+	sync := data.Get("sync").(bool)
+
+	if !info.IsDir() && len(excludes) > 0 {
+		return fmt.Errorf("'excludes' can only be used with a directory 'source', not a single file")
+	}
+
+	if info.IsDir() && (len(excludes) > 0 || sync) {
+		staged, err := stageFilteredDir(ctx, comm, connState, src, dst, excludes, sync)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(staged)
+		src = staged
+	}
+
+	if !info.IsDir() && sync {
+		unchanged, err := singleFileUnchanged(ctx, comm, connState, src, dst)
+		if err != nil {
+			log.Printf("[WARN] file provisioner: sync mode could not inspect the destination, uploading the file: %s", err)
+		} else if unchanged {
+			return nil
+		}
+	}
+
 	if err := copyFiles(ctx, comm, src, dst); err != nil {
 		return err
 	}
@@ -94,6 +157,257 @@ func getSrc(data *schema.ResourceData) (string, bool, error) {
 	return expansion, false, err
 }
 
+// excludePatterns returns the configured "excludes" glob patterns, matched
+// against each uploaded file's path relative to the source directory.
+func excludePatterns(data *schema.ResourceData) []string {
+	raw := data.Get("excludes").([]interface{})
+	if len(raw) == 0 {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if s, ok := p.(string); ok && s != "" {
+			patterns = append(patterns, s)
+		}
+	}
+	return patterns
+}
+
+// verifyChecksum confirms that the file at path hashes to the value given
+// in checksum, which is either a bare hex-encoded sha256 digest or a
+// "algorithm:digest" pair. sha256 is currently the only supported
+// algorithm.
+func verifyChecksum(path, checksum string) error {
+	algo := "sha256"
+	expected := checksum
+	if idx := strings.Index(checksum, ":"); idx != -1 {
+		algo = checksum[:idx]
+		expected = checksum[idx+1:]
+	}
+	if algo != "sha256" {
+		return fmt.Errorf("unsupported checksum algorithm %q: only \"sha256\" is supported", algo)
+	}
+
+	actual, err := sha256File(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %q: expected %s, got %s", path, expected, actual)
+	}
+	return nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stageFilteredDir builds a temporary copy of src that omits any file
+// matching an exclude pattern and, when sync is true, any file whose
+// content already matches what's at the destination. The returned
+// directory should be uploaded in place of src and removed afterwards.
+func stageFilteredDir(ctx context.Context, comm communicator.Communicator, connState *terraform.InstanceState, src, dst string, excludes []string, sync bool) (string, error) {
+	staged, err := ioutil.TempDir("", "tf-file-provisioner")
+	if err != nil {
+		return "", err
+	}
+
+	var unchanged map[string]string
+	if sync {
+		hashes, err := remoteFileHashes(ctx, comm, connState, dst)
+		if err != nil {
+			log.Printf("[WARN] file provisioner: sync mode could not inspect the destination, uploading all files: %s", err)
+		} else {
+			unchanged = hashes
+		}
+	}
+
+	walkErr := filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		relSlash := filepath.ToSlash(rel)
+
+		for _, pattern := range excludes {
+			matched, err := filepath.Match(pattern, relSlash)
+			if err != nil {
+				return fmt.Errorf("invalid exclude pattern %q: %s", pattern, err)
+			}
+			if matched {
+				return nil
+			}
+		}
+
+		if unchanged != nil {
+			hash, err := sha256File(path)
+			if err != nil {
+				return err
+			}
+			if unchanged[relSlash] == hash {
+				return nil
+			}
+		}
+
+		return copyLocalFile(path, filepath.Join(staged, rel), fi.Mode())
+	})
+	if walkErr != nil {
+		os.RemoveAll(staged)
+		return "", walkErr
+	}
+
+	return staged, nil
+}
+
+func copyLocalFile(src, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// remoteFileHashes returns a map from a file's path relative to dst to its
+// sha256 digest, for every regular file that already exists under dst on
+// the remote host, so that sync mode can skip re-uploading unchanged
+// files. Only the ssh connection type is supported; other connection
+// types return an error so the caller can fall back to uploading
+// everything.
+func remoteFileHashes(ctx context.Context, comm communicator.Communicator, connState *terraform.InstanceState, dst string) (map[string]string, error) {
+	connType := connState.Ephemeral.ConnInfo["type"]
+	if connType != "" && connType != "ssh" {
+		return nil, fmt.Errorf("sync mode is only supported for ssh connections, got %q", connType)
+	}
+
+	retryCtx, cancel := context.WithTimeout(ctx, comm.Timeout())
+	defer cancel()
+	if err := communicator.Retry(retryCtx, func() error {
+		return comm.Connect(nil)
+	}); err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := &remote.Cmd{
+		Command: fmt.Sprintf("find %s -type f -exec sha256sum {} \\; 2>/dev/null", shellQuote(dst)),
+		Stdout:  &stdout,
+	}
+	if err := comm.Start(cmd); err != nil {
+		return nil, err
+	}
+	// A non-zero exit (for example because the destination doesn't exist
+	// yet) just means there's nothing on the remote end to compare
+	// against, so treat it the same as an empty listing rather than an
+	// error.
+	cmd.Wait()
+
+	dstClean := strings.TrimRight(dst, "/") + "/"
+	hashes := make(map[string]string)
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		hash := fields[0]
+		path := strings.TrimLeft(fields[1], " *")
+		if !strings.HasPrefix(path, dstClean) {
+			continue
+		}
+
+		hashes[strings.TrimPrefix(path, dstClean)] = hash
+	}
+
+	return hashes, nil
+}
+
+// singleFileUnchanged reports whether the content already at dst on the
+// remote host matches src, so that applyFn's sync mode can skip
+// re-uploading a single-file source whose content hasn't changed. Only
+// the ssh connection type is supported; other connection types return an
+// error so the caller can fall back to uploading the file.
+func singleFileUnchanged(ctx context.Context, comm communicator.Communicator, connState *terraform.InstanceState, src, dst string) (bool, error) {
+	connType := connState.Ephemeral.ConnInfo["type"]
+	if connType != "" && connType != "ssh" {
+		return false, fmt.Errorf("sync mode is only supported for ssh connections, got %q", connType)
+	}
+
+	localHash, err := sha256File(src)
+	if err != nil {
+		return false, err
+	}
+
+	retryCtx, cancel := context.WithTimeout(ctx, comm.Timeout())
+	defer cancel()
+	if err := communicator.Retry(retryCtx, func() error {
+		return comm.Connect(nil)
+	}); err != nil {
+		return false, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := &remote.Cmd{
+		Command: fmt.Sprintf("sha256sum %s 2>/dev/null", shellQuote(dst)),
+		Stdout:  &stdout,
+	}
+	if err := comm.Start(cmd); err != nil {
+		return false, err
+	}
+	// A non-zero exit (for example because the destination doesn't exist
+	// yet) just means there's nothing on the remote end to compare
+	// against, so treat it the same as "changed" rather than an error.
+	cmd.Wait()
+
+	fields := strings.SplitN(strings.TrimSpace(stdout.String()), " ", 2)
+	if len(fields) != 2 {
+		return false, nil
+	}
+
+	return fields[0] == localHash, nil
+}
+
+// shellQuote wraps s in single quotes for safe inclusion in a POSIX shell
+// command line.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
 // copyFiles is used to copy the files from a source to a destination
 func copyFiles(ctx context.Context, comm communicator.Communicator, src, dst string) error {
 	retryCtx, cancel := context.WithTimeout(ctx, comm.Timeout())
@@ -1,6 +1,10 @@
 package file
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform/configs/hcl2shim"
@@ -110,3 +114,83 @@ func TestResourceProvider_Validate_bad_to_many_src(t *testing.T) {
 func testConfig(t *testing.T, c map[string]interface{}) *terraform.ResourceConfig {
 	return terraform.NewResourceConfigRaw(c)
 }
+
+func TestVerifyChecksum(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf-file-checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tf.Name())
+	if _, err := tf.WriteString("hello world"); err != nil {
+		t.Fatal(err)
+	}
+	tf.Close()
+
+	const sha256sum = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+
+	if err := verifyChecksum(tf.Name(), sha256sum); err != nil {
+		t.Fatalf("expected bare sha256 checksum to verify: %s", err)
+	}
+	if err := verifyChecksum(tf.Name(), "sha256:"+sha256sum); err != nil {
+		t.Fatalf("expected prefixed sha256 checksum to verify: %s", err)
+	}
+	if err := verifyChecksum(tf.Name(), "0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Fatal("expected checksum mismatch to fail")
+	}
+	if err := verifyChecksum(tf.Name(), "md5:5eb63bbbe01eeed093cb22bb8f5acdc3"); err == nil {
+		t.Fatal("expected unsupported algorithm to fail")
+	}
+}
+
+func TestStageFilteredDir_excludes(t *testing.T) {
+	src, err := ioutil.TempDir("", "tf-file-src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	for _, name := range []string{"keep.txt", "skip.log", "nested/keep.txt", "nested/skip.log"} {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	staged, err := stageFilteredDir(context.Background(), nil, &terraform.InstanceState{}, src, "/tmp/dst", []string{"*.log", "nested/*.log"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(staged)
+
+	for _, name := range []string{"keep.txt", "nested/keep.txt"} {
+		if _, err := os.Stat(filepath.Join(staged, name)); err != nil {
+			t.Errorf("expected %s to be staged: %s", name, err)
+		}
+	}
+	for _, name := range []string{"skip.log", "nested/skip.log"} {
+		if _, err := os.Stat(filepath.Join(staged, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be excluded, got err: %v", name, err)
+		}
+	}
+}
+
+func TestSingleFileUnchanged_unsupportedConnType(t *testing.T) {
+	tf, err := ioutil.TempFile("", "tf-file-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tf.Name())
+
+	connState := &terraform.InstanceState{
+		Ephemeral: terraform.EphemeralState{
+			ConnInfo: map[string]string{"type": "winrm"},
+		},
+	}
+
+	if _, err := singleFileUnchanged(context.Background(), nil, connState, tf.Name(), "/tmp/dst.txt"); err == nil {
+		t.Fatal("expected an error for a non-ssh connection type")
+	}
+}
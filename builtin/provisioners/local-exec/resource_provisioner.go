@@ -1,12 +1,16 @@
 package localexec
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"runtime"
+	"time"
 
 	"github.com/armon/circbuf"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -41,6 +45,34 @@ func Provisioner() terraform.ResourceProvisioner {
 				Type:     schema.TypeMap,
 				Optional: true,
 			},
+			"attributes": &schema.Schema{
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+			},
+			"stdin_json": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"json_file": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"timeout": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"retries": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"retry_wait": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "1s",
+			},
 		},
 
 		ApplyFunc: applyFn,
@@ -48,6 +80,7 @@ func Provisioner() terraform.ResourceProvisioner {
 }
 
 func applyFn(ctx context.Context) error {
+	state := ctx.Value(schema.ProvRawStateKey).(*terraform.InstanceState)
 	data := ctx.Value(schema.ProvConfigDataKey).(*schema.ResourceData)
 	o := ctx.Value(schema.ProvOutputKey).(terraform.UIOutput)
 
@@ -65,6 +98,63 @@ func applyFn(ctx context.Context) error {
 		env = append(env, entry)
 	}
 
+	attrsJSON, err := selectedAttributesJSON(state, data.Get("attributes").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	jsonFile := data.Get("json_file").(string)
+	if jsonFile != "" && attrsJSON != nil {
+		if err := ioutil.WriteFile(jsonFile, attrsJSON, 0600); err != nil {
+			return fmt.Errorf("failed to write attributes to json_file %q: %s", jsonFile, err)
+		}
+		defer os.Remove(jsonFile)
+		env = append(env, fmt.Sprintf("TF_LOCAL_EXEC_JSON_FILE=%s", jsonFile))
+	}
+
+	stdinJSON := data.Get("stdin_json").(bool)
+
+	retries := data.Get("retries").(int)
+	retryWait, err := parseDuration(data.Get("retry_wait").(string), time.Second)
+	if err != nil {
+		return fmt.Errorf("invalid retry_wait: %s", err)
+	}
+
+	var timeout time.Duration
+	if raw := data.Get("timeout").(string); raw != "" {
+		timeout, err = parseDuration(raw, 0)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %s", err)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			o.Output(fmt.Sprintf("Retrying (attempt %d/%d) after error: %v", attempt, retries, lastErr))
+			select {
+			case <-time.After(retryWait):
+			case <-ctx.Done():
+				return lastErr
+			}
+		}
+
+		lastErr = runCommand(ctx, o, command, env, data, attrsJSON, stdinJSON, timeout)
+		if lastErr == nil {
+			return nil
+		}
+	}
+
+	return lastErr
+}
+
+func runCommand(ctx context.Context, o terraform.UIOutput, command string, env []string, data *schema.ResourceData, attrsJSON []byte, stdinJSON bool, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	// Execute the command using a shell
 	interpreter := data.Get("interpreter").([]interface{})
 
@@ -103,6 +193,9 @@ func applyFn(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, cmdargs[0], cmdargs[1:]...)
 	cmd.Stderr = pw
 	cmd.Stdout = pw
+	if stdinJSON && attrsJSON != nil {
+		cmd.Stdin = bytes.NewReader(attrsJSON)
+	}
 	// Dir specifies the working directory of the command.
 	// If Dir is the empty string (this is default), runs the command
 	// in the calling process's current directory.
@@ -149,6 +242,36 @@ func applyFn(ctx context.Context) error {
 	return nil
 }
 
+// selectedAttributesJSON returns the given resource attributes, drawn from
+// the instance's flat attribute map, marshaled as a JSON object. It returns
+// nil if no attributes were requested or no state is available yet (as is
+// the case for a create-time provisioner's very first invocation in some
+// test harnesses).
+func selectedAttributesJSON(state *terraform.InstanceState, attrs []interface{}) ([]byte, error) {
+	if state == nil || len(attrs) == 0 {
+		return nil, nil
+	}
+
+	selected := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		name, ok := a.(string)
+		if !ok || name == "" {
+			continue
+		}
+		selected[name] = state.Attributes[name]
+	}
+
+	return json.Marshal(selected)
+}
+
+// parseDuration parses a Go duration string, returning def if s is empty.
+func parseDuration(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	return time.ParseDuration(s)
+}
+
 func copyOutput(o terraform.UIOutput, r io.Reader, doneCh chan<- struct{}) {
 	defer close(doneCh)
 	lr := linereader.New(r)
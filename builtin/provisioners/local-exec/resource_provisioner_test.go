@@ -169,6 +169,50 @@ func TestResourceProvider_ApplyCustomWorkingDirectory(t *testing.T) {
 	}
 }
 
+func TestResourceProvider_ApplyStdinJSON(t *testing.T) {
+	c := testConfig(t, map[string]interface{}{
+		"command":    "cat",
+		"stdin_json": true,
+		"attributes": []interface{}{"id", "missing"},
+	})
+
+	state := &terraform.InstanceState{
+		Attributes: map[string]string{
+			"id":  "i-abc123",
+			"ami": "ami-abc123",
+		},
+	}
+
+	output := new(terraform.MockUIOutput)
+	p := Provisioner()
+
+	if err := p.Apply(output, state, c); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	got := strings.TrimSpace(output.OutputMessage)
+	want := `{"id":"i-abc123","missing":""}`
+	if got != want {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestResourceProvider_ApplyRetries(t *testing.T) {
+	defer os.Remove("retry_marker")
+	c := testConfig(t, map[string]interface{}{
+		"command":    "test -f retry_marker || { touch retry_marker; exit 1; }",
+		"retries":    1,
+		"retry_wait": "1ms",
+	})
+
+	output := new(terraform.MockUIOutput)
+	p := Provisioner()
+
+	if err := p.Apply(output, nil, c); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestResourceProvider_ApplyCustomEnv(t *testing.T) {
 	c := testConfig(t, map[string]interface{}{
 		"command": "echo $FOO $BAR $BAZ",
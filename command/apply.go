@@ -28,6 +28,8 @@ type ApplyCommand struct {
 
 func (c *ApplyCommand) Run(args []string) int {
 	var destroyForce, refresh, autoApprove bool
+	var maxDestroy, maxChange int
+	var resultFile string
 	args = c.Meta.process(args)
 	cmdName := "apply"
 	if c.Destroy {
@@ -40,6 +42,9 @@ func (c *ApplyCommand) Run(args []string) int {
 		cmdFlags.BoolVar(&destroyForce, "force", false, "deprecated: same as auto-approve")
 	}
 	cmdFlags.BoolVar(&refresh, "refresh", true, "refresh")
+	cmdFlags.IntVar(&maxDestroy, "max-destroy", 0, "abort an auto-approved apply if the plan would destroy more than this many resource instances")
+	cmdFlags.IntVar(&maxChange, "max-change", 0, "abort an auto-approved apply if the plan would make more than this many changes")
+	cmdFlags.StringVar(&resultFile, "result-file", "", "path to write a JSON summary of the apply outcome")
 	cmdFlags.IntVar(&c.Meta.parallelism, "parallelism", DefaultParallelism, "parallelism")
 	cmdFlags.StringVar(&c.Meta.statePath, "state", "", "path")
 	cmdFlags.StringVar(&c.Meta.stateOutPath, "state-out", "", "path")
@@ -176,6 +181,9 @@ func (c *ApplyCommand) Run(args []string) int {
 	opReq.ConfigDir = configPath
 	opReq.Destroy = c.Destroy
 	opReq.DestroyForce = destroyForce
+	opReq.MaxDestroy = maxDestroy
+	opReq.MaxChange = maxChange
+	opReq.ResultFile = resultFile
 	opReq.PlanFile = planFile
 	opReq.PlanRefresh = refresh
 	opReq.Type = backend.OperationTypeApply
@@ -260,6 +268,17 @@ Options:
 
   -input=true            Ask for input for variables if not directly set.
 
+  -max-change=n          Abort an auto-approved apply, before making any
+                         changes, if the plan would make more than n
+                         changes (create, update, or destroy, combined).
+                         Has no effect without -auto-approve. Defaults to 0,
+                         meaning no limit.
+
+  -max-destroy=n         Abort an auto-approved apply, before making any
+                         changes, if the plan would destroy more than n
+                         resource instances. Has no effect without
+                         -auto-approve. Defaults to 0, meaning no limit.
+
   -no-color              If specified, output won't contain any color.
 
   -parallelism=n         Limit the number of parallel resource operations.
@@ -268,6 +287,12 @@ Options:
   -refresh=true          Update state prior to checking for differences. This
                          has no effect if a plan file is given to apply.
 
+  -result-file=path      Path to write a JSON summary of the apply outcome,
+                         including per-resource success/failure, the final
+                         output values, and the new state serial. Useful for
+                         deployment pipelines that need to gate subsequent
+                         steps without parsing console output.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -311,6 +336,16 @@ Options:
 
   -lock-timeout=0s       Duration to retry a state lock.
 
+  -max-change=n          Abort an auto-approved destroy, before making any
+                         changes, if the plan would make more than n
+                         changes. Has no effect without -auto-approve.
+                         Defaults to 0, meaning no limit.
+
+  -max-destroy=n         Abort an auto-approved destroy, before making any
+                         changes, if the plan would destroy more than n
+                         resource instances. Has no effect without
+                         -auto-approve. Defaults to 0, meaning no limit.
+
   -no-color              If specified, output won't contain any color.
 
   -parallelism=n         Limit the number of concurrent operations.
@@ -319,6 +354,12 @@ Options:
   -refresh=true          Update state prior to checking for differences. This
                          has no effect if a plan file is given to apply.
 
+  -result-file=path      Path to write a JSON summary of the destroy outcome,
+                         including per-resource success/failure and the new
+                         state serial. Useful for deployment pipelines that
+                         need to gate subsequent steps without parsing
+                         console output.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -0,0 +1,334 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+// FixProvidersCommand is a Command implementation that looks for child
+// module calls that use an aliased provider configuration without passing
+// it down explicitly, and suggests (or, with -fix, writes) the "providers"
+// map entry that's missing.
+type FixProvidersCommand struct {
+	Meta
+}
+
+func (c *FixProvidersCommand) Help() string {
+	return fixProvidersCommandHelp
+}
+
+func (c *FixProvidersCommand) Synopsis() string {
+	return "Finds and optionally fixes missing provider passes to child modules"
+}
+
+func (c *FixProvidersCommand) Run(args []string) int {
+	var fix bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("fix-providers")
+	cmdFlags.BoolVar(&fix, "fix", false, "fix")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	configPath, err := ModulePath(cmdFlags.Args())
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	empty, err := configs.IsEmptyDir(configPath)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error validating configuration directory",
+			fmt.Sprintf("Terraform encountered an unexpected error while verifying that the given configuration directory is valid: %s.", err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if empty {
+		absPath, err := filepath.Abs(configPath)
+		if err != nil {
+			absPath = configPath
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No configuration files",
+			fmt.Sprintf("The directory %s contains no Terraform configuration files.", absPath),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	config, configDiags := c.loadConfig(configPath)
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	gaps := findMissingProviderPasses(config)
+	if len(gaps) == 0 {
+		c.Ui.Output("All module calls pass along the aliased provider configurations their child modules use.")
+		return 0
+	}
+
+	for _, gap := range gaps {
+		c.Ui.Output(fmt.Sprintf(
+			"%s: module call %q does not pass a provider configuration for %s\n\n  Suggested fix:\n\n    providers = {\n      %s = %s\n    }\n",
+			gap.CallRange, gap.CallName, gap.ChildRef.String(), gap.ChildRef.StringCompact(), gap.ParentRef.StringCompact(),
+		))
+
+		if fix {
+			if err := applyProviderPassFix(gap); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Unable to apply fix",
+					fmt.Sprintf("Error updating %s: %s", gap.CallRange.Filename, err),
+				))
+			}
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	if fix {
+		c.Ui.Output("\nUpdated configuration to pass the missing provider configurations.")
+	}
+	return 0
+}
+
+// providerPassGap describes a child module call that uses an aliased
+// provider configuration which its caller does not explicitly pass down.
+type providerPassGap struct {
+	CallName  string
+	CallRange hcl.Range
+
+	// ChildRef is the aliased provider configuration as referenced inside
+	// the child module.
+	ChildRef addrs.LocalProviderConfig
+
+	// ParentRef is our best guess at the provider configuration in the
+	// caller that should be passed down to satisfy ChildRef. In the common
+	// case this has the same local name and alias as ChildRef; callers that
+	// named their provider configuration differently will need to adjust
+	// the suggestion by hand.
+	ParentRef addrs.LocalProviderConfig
+}
+
+// findMissingProviderPasses walks the given configuration's module tree
+// looking for module calls whose child module makes use of an aliased
+// provider configuration that the call does not pass down via its
+// "providers" argument.
+//
+// Aliased provider configurations are never inherited implicitly, so a
+// missing entry here is always a real gap rather than a stylistic
+// preference.
+func findMissingProviderPasses(root *configs.Config) []providerPassGap {
+	var gaps []providerPassGap
+
+	callNames := make([]string, 0, len(root.Children))
+	for name := range root.Children {
+		callNames = append(callNames, name)
+	}
+	sort.Strings(callNames)
+
+	for _, name := range callNames {
+		child := root.Children[name]
+		call := root.Module.ModuleCalls[name]
+		if call == nil {
+			continue
+		}
+
+		passed := make(map[addrs.LocalProviderConfig]bool)
+		for _, pc := range call.Providers {
+			passed[pc.InChild.Addr()] = true
+		}
+
+		used := make(map[addrs.LocalProviderConfig]bool)
+		for _, rc := range child.Module.ManagedResources {
+			if rc.ProviderConfigRef != nil && rc.ProviderConfigRef.Alias != "" {
+				used[rc.ProviderConfigAddr()] = true
+			}
+		}
+		for _, rc := range child.Module.DataResources {
+			if rc.ProviderConfigRef != nil && rc.ProviderConfigRef.Alias != "" {
+				used[rc.ProviderConfigAddr()] = true
+			}
+		}
+
+		refs := make([]addrs.LocalProviderConfig, 0, len(used))
+		for ref := range used {
+			refs = append(refs, ref)
+		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+
+		for _, ref := range refs {
+			if passed[ref] {
+				continue
+			}
+			gaps = append(gaps, providerPassGap{
+				CallName:  name,
+				CallRange: call.DeclRange,
+				ChildRef:  ref,
+				ParentRef: ref,
+			})
+		}
+
+		gaps = append(gaps, findMissingProviderPasses(child)...)
+	}
+
+	return gaps
+}
+
+// applyProviderPassFix rewrites the module block identified by the given
+// gap, in place, to add a "providers" map entry that passes the suggested
+// parent provider configuration down to the child.
+func applyProviderPassFix(gap providerPassGap) error {
+	filename := gap.CallRange.Filename
+
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	block := findModuleBlock(f.Body(), gap.CallName)
+	if block == nil {
+		return fmt.Errorf("could not find module %q in %s", gap.CallName, filename)
+	}
+
+	if err := setProviderPassAttr(block.Body(), gap.ChildRef, gap.ParentRef); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, f.Bytes(), 0644)
+}
+
+func findModuleBlock(body *hclwrite.Body, name string) *hclwrite.Block {
+	for _, block := range body.Blocks() {
+		if block.Type() != "module" {
+			continue
+		}
+		labels := block.Labels()
+		if len(labels) == 1 && labels[0] == name {
+			return block
+		}
+	}
+	return nil
+}
+
+// setProviderPassAttr adds childRef -> parentRef to the "providers" map
+// attribute of the given module call body, creating the attribute if it
+// doesn't already exist.
+//
+// If the call already has a "providers" attribute whose existing entries
+// can't be parsed back out, this returns an error rather than overwriting
+// the attribute, since doing so would silently discard whatever entries
+// were already there.
+func setProviderPassAttr(body *hclwrite.Body, childRef, parentRef addrs.LocalProviderConfig) error {
+	attr := body.GetAttribute("providers")
+
+	entries := map[string]string{}
+	if attr != nil {
+		parsed, err := parseProviderPassEntries(attr.Expr().BuildTokens(nil))
+		if err != nil {
+			return fmt.Errorf("could not parse existing \"providers\" argument: %s", err)
+		}
+		for k, v := range parsed {
+			entries[k] = v
+		}
+	}
+	entries[childRef.StringCompact()] = parentRef.StringCompact()
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	src := "{\n"
+	for _, k := range keys {
+		src += fmt.Sprintf("    %s = %s\n", k, entries[k])
+	}
+	src += "  }\n"
+
+	exprFile, diags := hclwrite.ParseConfig([]byte("providers = "+src), "<fix-providers>", hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+	tokens := exprFile.Body().GetAttribute("providers").Expr().BuildTokens(nil)
+	body.SetAttributeRaw("providers", tokens)
+	return nil
+}
+
+// parseProviderPassEntries re-parses an existing "providers = { ... }"
+// expression's tokens back into a name->value map, so that
+// setProviderPassAttr can preserve entries that were already present.
+//
+// It returns an error if the expression isn't a literal object
+// constructor that we know how to round-trip, rather than silently
+// dropping entries we can't account for.
+func parseProviderPassEntries(tokens hclwrite.Tokens) (map[string]string, error) {
+	entries := map[string]string{}
+
+	src := append([]byte("providers = "), tokens.Bytes()...)
+	f, diags := hclsyntax.ParseConfig(src, "<fix-providers>", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	content, _, diags := f.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "providers"}},
+	})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	attr, ok := content.Attributes["providers"]
+	if !ok {
+		return entries, nil
+	}
+
+	items, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("\"providers\" must be a literal object constructor: %s", diags)
+	}
+	for _, item := range items {
+		key := string(item.Key.Range().SliceBytes(src))
+		value := string(item.Value.Range().SliceBytes(src))
+		entries[key] = value
+	}
+	return entries, nil
+}
+
+const fixProvidersCommandHelp = `
+Usage: terraform fix-providers [dir]
+
+  Looks for child module calls that use an aliased provider configuration
+  without passing it down explicitly via a "providers" argument, and
+  prints the map entry that's missing, including the alias.
+
+Options:
+
+  -fix    In addition to reporting each gap, rewrite the calling module
+          block to add the suggested "providers" entry.
+`
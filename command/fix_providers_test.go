@@ -0,0 +1,126 @@
+package command
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/addrs"
+)
+
+func TestApplyProviderPassFix(t *testing.T) {
+	dir := testTempDir(t)
+	filename := filepath.Join(dir, "main.tf")
+	if err := ioutil.WriteFile(filename, []byte(`
+module "child" {
+  source = "./child"
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gap := providerPassGap{
+		CallName: "child",
+		CallRange: hcl.Range{
+			Filename: filename,
+		},
+		ChildRef:  addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+		ParentRef: addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+	}
+
+	if err := applyProviderPassFix(gap); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	got, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `aws.west = aws.west`) {
+		t.Fatalf("missing provider pass entry in result:\n%s", got)
+	}
+}
+
+// TestApplyProviderPassFix_preservesExistingEntries ensures that applying a
+// fix to a module call that already passes along other provider
+// configurations doesn't drop those entries.
+func TestApplyProviderPassFix_preservesExistingEntries(t *testing.T) {
+	dir := testTempDir(t)
+	filename := filepath.Join(dir, "main.tf")
+	if err := ioutil.WriteFile(filename, []byte(`
+module "child" {
+  source = "./child"
+  providers = {
+    aws.east = aws.east
+  }
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gap := providerPassGap{
+		CallName: "child",
+		CallRange: hcl.Range{
+			Filename: filename,
+		},
+		ChildRef:  addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+		ParentRef: addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+	}
+
+	if err := applyProviderPassFix(gap); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	got, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `aws.east = aws.east`) {
+		t.Fatalf("lost pre-existing provider pass entry in result:\n%s", got)
+	}
+	if !strings.Contains(string(got), `aws.west = aws.west`) {
+		t.Fatalf("missing new provider pass entry in result:\n%s", got)
+	}
+}
+
+// TestApplyProviderPassFix_nonLiteralExisting ensures that a module call
+// whose existing "providers" argument isn't a literal object constructor
+// (so we can't safely round-trip its entries) is left untouched rather
+// than having its providers map silently overwritten with only the new
+// entry.
+func TestApplyProviderPassFix_nonLiteralExisting(t *testing.T) {
+	dir := testTempDir(t)
+	filename := filepath.Join(dir, "main.tf")
+	original := `
+module "child" {
+  source    = "./child"
+  providers = local.provider_map
+}
+`
+	if err := ioutil.WriteFile(filename, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gap := providerPassGap{
+		CallName: "child",
+		CallRange: hcl.Range{
+			Filename: filename,
+		},
+		ChildRef:  addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+		ParentRef: addrs.LocalProviderConfig{LocalName: "aws", Alias: "west"},
+	}
+
+	if err := applyProviderPassFix(gap); err == nil {
+		t.Fatal("expected an error, but applyProviderPassFix succeeded")
+	}
+
+	got, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != original {
+		t.Fatalf("file was modified despite the error:\ngot:  %s\nwant: %s", got, original)
+	}
+}
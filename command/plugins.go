@@ -130,8 +130,25 @@ func (m *Meta) pluginCache() discovery.PluginCache {
 	return discovery.NewLocalPluginCache(dir)
 }
 
+// ProvisionerMirrorDirEnvVar, if set, names an additional directory to
+// search for provisioner plugins, after the usual plugin directories
+// returned by pluginDirs. This gives teams a way to distribute custom
+// provisioners through a shared local mirror without placing them
+// alongside the Terraform executable or in the working directory.
+//
+// This is a deliberately small step towards the kind of third-party
+// provisioner distribution that providers already get via the provider
+// registry and its filesystem/network mirrors (see internal/getproviders):
+// full registry-based discovery and installation for provisioners would
+// require a much larger protocol and CLI overhaul, so it isn't attempted
+// here.
+const ProvisionerMirrorDirEnvVar = "TF_PROVISIONER_MIRROR_DIR"
+
 func (m *Meta) provisionerFactories() map[string]terraform.ProvisionerFactory {
 	dirs := m.pluginDirs(true)
+	if mirrorDir := os.Getenv(ProvisionerMirrorDirEnvVar); mirrorDir != "" {
+		dirs = append(dirs, mirrorDir)
+	}
 	plugins := discovery.FindPlugins("provisioner", dirs)
 	plugins, _ = plugins.ValidateVersions()
 
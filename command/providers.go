@@ -25,8 +25,11 @@ func (c *ProvidersCommand) Synopsis() string {
 }
 
 func (c *ProvidersCommand) Run(args []string) int {
+	var explain bool
+
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("providers")
+	cmdFlags.BoolVar(&explain, "explain", false, "explain")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -106,10 +109,14 @@ func (c *ProvidersCommand) Run(args []string) int {
 		reqs = reqs.Merge(stateReqs)
 	}
 
-	printRoot := treeprint.New()
-	providersCommandPopulateTreeNode(printRoot, reqs)
+	if explain {
+		c.Ui.Output(providersExplainReport(config))
+	} else {
+		printRoot := treeprint.New()
+		providersCommandPopulateTreeNode(printRoot, reqs)
 
-	c.Ui.Output(printRoot.String())
+		c.Ui.Output(printRoot.String())
+	}
 
 	c.showDiagnostics(diags)
 	if diags.HasErrors() {
@@ -136,4 +143,12 @@ Usage: terraform providers [dir]
   This provides an overview of all of the provider requirements as an aid to
   understanding why particular provider plugins are needed and why particular
   versions are selected.
+
+Options:
+
+  -explain    Instead of the flat requirements tree, show which module
+              introduced each provider requirement and whether each module
+              call passes providers explicitly or relies on implicit
+              inheritance. This is intended to help debug "provider
+              configuration not present" errors.
 `
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/configs"
+	"github.com/xlab/treeprint"
+)
+
+// providersExplainReport builds a human-readable tree describing, for each
+// module in the given configuration, which provider requirements it
+// introduces and whether each of its child module calls passes providers
+// explicitly or relies on implicit inheritance.
+//
+// This is intended to help debug the frequent "provider configuration not
+// present" class of errors, where a child module ends up with a different
+// idea of a provider's configuration than its caller intended.
+func providersExplainReport(root *configs.Config) string {
+	tree := treeprint.New()
+	explainModule(tree, root)
+	return tree.String()
+}
+
+func explainModule(node treeprint.Tree, c *configs.Config) {
+	label := "root"
+	if len(c.Path) > 0 {
+		label = c.Path.String()
+	}
+	modNode := node.AddBranch(fmt.Sprintf("module[%s]", label))
+
+	explicitFQNs := make(map[addrs.Provider]bool)
+	for _, req := range c.Module.ProviderRequirements.RequiredProviders {
+		explicitFQNs[req.Type] = true
+		versionsStr := req.Requirement.Required.String()
+		if versionsStr != "" {
+			versionsStr = " " + versionsStr
+		}
+		modNode.AddNode(fmt.Sprintf("provider[%s]%s (explicit requirement)", req.Type, versionsStr))
+	}
+
+	implicit := make(map[addrs.Provider]bool)
+	for _, rc := range c.Module.ManagedResources {
+		if !explicitFQNs[rc.Provider] {
+			implicit[rc.Provider] = true
+		}
+	}
+	for _, rc := range c.Module.DataResources {
+		if !explicitFQNs[rc.Provider] {
+			implicit[rc.Provider] = true
+		}
+	}
+	fqns := make([]addrs.Provider, 0, len(implicit))
+	for fqn := range implicit {
+		fqns = append(fqns, fqn)
+	}
+	sort.Slice(fqns, func(i, j int) bool { return fqns[i].String() < fqns[j].String() })
+	for _, fqn := range fqns {
+		modNode.AddNode(fmt.Sprintf("provider[%s] (implicit, inferred from resource usage)", fqn))
+	}
+
+	callNames := make([]string, 0, len(c.Children))
+	for name := range c.Children {
+		callNames = append(callNames, name)
+	}
+	sort.Strings(callNames)
+
+	for _, name := range callNames {
+		child := c.Children[name]
+		call := c.Module.ModuleCalls[name]
+
+		if call != nil && len(call.Providers) > 0 {
+			var pairs []string
+			for _, pc := range call.Providers {
+				pairs = append(pairs, fmt.Sprintf("%s <- %s", pc.InChild.String(), pc.InParent.String()))
+			}
+			modNode.AddNode(fmt.Sprintf("call[%s]: providers passed explicitly (%s)", name, strings.Join(pairs, ", ")))
+		} else {
+			modNode.AddNode(fmt.Sprintf("call[%s]: providers inherited implicitly from %s", name, label))
+		}
+
+		explainModule(modNode, child)
+	}
+}
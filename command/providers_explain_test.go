@@ -0,0 +1,47 @@
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	version "github.com/hashicorp/go-version"
+
+	"github.com/hashicorp/terraform/configs"
+)
+
+func TestProvidersExplainReport(t *testing.T) {
+	fixtureDir := filepath.Join(testFixturePath("providers-explain"))
+
+	parser := configs.NewParser(nil)
+	mod, diags := parser.LoadConfigDir(fixtureDir)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	config, diags := configs.BuildConfig(mod, configs.ModuleWalkerFunc(
+		func(req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
+			sourcePath := filepath.Join(fixtureDir, req.SourceAddr)
+			mod, diags := parser.LoadConfigDir(sourcePath)
+			return mod, nil, diags
+		},
+	))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	got := providersExplainReport(config)
+
+	for _, want := range []string{
+		"module[root]",
+		"provider[registry.terraform.io/hashicorp/foo] 1.0 (explicit requirement)",
+		`call[child]: providers inherited implicitly from root`,
+		"module[module.child]",
+		"provider[registry.terraform.io/hashicorp/baz] (implicit, inferred from resource usage)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report does not contain %q\ngot:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/backend"
+	"github.com/hashicorp/terraform/dag"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// RefsCommand is a Command implementation that prints everything that
+// references a given resource or output address, and everything that
+// address itself references, without requiring the caller to read the
+// whole dependency graph.
+type RefsCommand struct {
+	Meta
+}
+
+func (c *RefsCommand) Run(args []string) int {
+	var depth int
+	var verbose bool
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("refs")
+	cmdFlags.IntVar(&depth, "depth", 1, "how many hops to follow in each direction")
+	cmdFlags.BoolVar(&verbose, "verbose", false, "verbose")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The refs command expects exactly one address argument.\n")
+		return cli.RunResultHelp
+	}
+	target := args[0]
+
+	configPath, err := ModulePath(nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	b, backendDiags := c.Backend(&BackendOpts{Config: backendConfig})
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	opReq := c.Operation(b)
+	opReq.ConfigDir = configPath
+	opReq.AllowUnsetVariables = true
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	ctx, _, ctxDiags := local.Context(opReq)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(ctxDiags)
+		return 1
+	}
+
+	g, graphDiags := ctx.Graph(terraform.GraphTypePlan, &terraform.ContextGraphOpts{
+		Verbose:  verbose,
+		Validate: false,
+	})
+	if graphDiags.HasErrors() {
+		c.showDiagnostics(graphDiags)
+		return 1
+	}
+
+	root := findVertexByAddr(g, target)
+	if root == nil {
+		c.Ui.Error(fmt.Sprintf("No graph node found matching address %q.", target))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("%s references:", target))
+	c.printRefs(g, root, depth, g.DownEdges, "  ")
+
+	c.Ui.Output(fmt.Sprintf("\n%s is referenced by:", target))
+	c.printRefs(g, root, depth, g.UpEdges, "  ")
+
+	return 0
+}
+
+// printRefs performs a breadth-first walk of edges up to the given depth
+// (0 meaning unlimited), printing one indented line per vertex found.
+func (c *RefsCommand) printRefs(g *terraform.Graph, root dag.Vertex, depth int, edgesFn func(dag.Vertex) dag.Set, indent string) {
+	seen := map[string]bool{dag.VertexName(root): true}
+	frontier := []dag.Vertex{root}
+	found := false
+
+	for level := 0; frontier != nil && (depth <= 0 || level < depth); level++ {
+		var next []dag.Vertex
+		for _, v := range frontier {
+			var names []string
+			for _, adj := range edgesFn(v).List() {
+				name := dag.VertexName(adj)
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				names = append(names, name)
+				next = append(next, adj.(dag.Vertex))
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				found = true
+				c.Ui.Output(fmt.Sprintf("%s%s", strings.Repeat(indent, level+1), name))
+			}
+		}
+		frontier = next
+	}
+
+	if !found {
+		c.Ui.Output(indent + "(none)")
+	}
+}
+
+// findVertexByAddr looks for a graph vertex whose name is the given address,
+// or is the given address followed by an instance key or annotation
+// (e.g. "aws_instance.example[0]").
+func findVertexByAddr(g *terraform.Graph, addr string) dag.Vertex {
+	for _, v := range g.Vertices() {
+		name := dag.VertexName(v)
+		if name == addr || strings.HasPrefix(name, addr+"[") || strings.HasPrefix(name, addr+" ") {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *RefsCommand) Help() string {
+	helpText := `
+Usage: terraform refs [options] ADDRESS
+
+  Prints everything that references the given resource or output address,
+  and everything that address itself references, resolved from the
+  configuration.
+
+  This is intended to answer "what breaks if I delete this?" without
+  requiring the whole dependency graph to be read as a DOT file.
+
+Options:
+
+  -depth=1     How many hops to follow in each direction. A value of 0
+               follows references transitively with no limit.
+
+  -verbose     Include "meta" nodes that are not directly connected to
+               configuration constructs.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RefsCommand) Synopsis() string {
+	return "Show what references an address and what it references"
+}
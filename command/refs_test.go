@@ -0,0 +1,86 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/dag"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/cli"
+)
+
+// namedTestVertex is a minimal dag.NamedVertex for exercising refs.go's
+// graph-walking helpers without building a real configuration graph.
+type namedTestVertex string
+
+func (v namedTestVertex) Name() string {
+	return string(v)
+}
+
+func TestFindVertexByAddr(t *testing.T) {
+	g := &terraform.Graph{}
+	a := namedTestVertex("aws_instance.foo")
+	b := namedTestVertex(`aws_instance.bar[0]`)
+	g.Add(a)
+	g.Add(b)
+
+	if got := findVertexByAddr(g, "aws_instance.foo"); got != dag.Vertex(a) {
+		t.Fatalf("wrong result for exact match: %#v", got)
+	}
+	if got := findVertexByAddr(g, "aws_instance.bar"); got != dag.Vertex(b) {
+		t.Fatalf("wrong result for indexed match: %#v", got)
+	}
+	if got := findVertexByAddr(g, "aws_instance.baz"); got != nil {
+		t.Fatalf("expected no match, got %#v", got)
+	}
+}
+
+func TestRefsCommand_printRefs(t *testing.T) {
+	g := &terraform.Graph{}
+	root := namedTestVertex("aws_instance.foo")
+	dep1 := namedTestVertex("aws_instance.bar")
+	dep2 := namedTestVertex("aws_instance.baz")
+	transitive := namedTestVertex("aws_instance.qux")
+	g.Add(root)
+	g.Add(dep1)
+	g.Add(dep2)
+	g.Add(transitive)
+	g.Connect(dag.BasicEdge(root, dep1))
+	g.Connect(dag.BasicEdge(root, dep2))
+	g.Connect(dag.BasicEdge(dep1, transitive))
+
+	ui := cli.NewMockUi()
+	c := &RefsCommand{Meta: Meta{Ui: ui}}
+
+	c.printRefs(g, root, 1, g.DownEdges, "  ")
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "aws_instance.bar") || !strings.Contains(out, "aws_instance.baz") {
+		t.Fatalf("expected direct references in output, got:\n%s", out)
+	}
+	if strings.Contains(out, "aws_instance.qux") {
+		t.Fatalf("depth=1 should not include transitive references, got:\n%s", out)
+	}
+
+	ui = cli.NewMockUi()
+	c = &RefsCommand{Meta: Meta{Ui: ui}}
+	c.printRefs(g, root, 0, g.DownEdges, "  ")
+	out = ui.OutputWriter.String()
+	if !strings.Contains(out, "aws_instance.qux") {
+		t.Fatalf("depth=0 should follow transitive references, got:\n%s", out)
+	}
+}
+
+func TestRefsCommand_printRefsNone(t *testing.T) {
+	g := &terraform.Graph{}
+	root := namedTestVertex("aws_instance.foo")
+	g.Add(root)
+
+	ui := cli.NewMockUi()
+	c := &RefsCommand{Meta: Meta{Ui: ui}}
+	c.printRefs(g, root, 1, g.DownEdges, "  ")
+
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "(none)") {
+		t.Fatalf("expected (none) marker in output, got:\n%s", out)
+	}
+}
@@ -0,0 +1,179 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/backend"
+	"github.com/hashicorp/terraform/command/clistate"
+	"github.com/mitchellh/cli"
+)
+
+// StateUpgradeCommand is a Command implementation that pre-runs any
+// provider-driven state schema upgrades that would otherwise happen
+// silently during the next refresh, and reports what it found.
+type StateUpgradeCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateUpgradeCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var dryRun bool
+	cmdFlags := c.Meta.defaultFlagSet("state upgrade")
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "don't persist the upgraded state")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock states")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return cli.RunResultHelp
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The state upgrade command expects no arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting cwd: %s", err))
+		return 1
+	}
+
+	opReq := c.Operation(b)
+	opReq.AllowUnsetVariables = true
+	opReq.ConfigDir = cwd
+
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing config loader: %s", err))
+		return 1
+	}
+
+	ctx, _, ctxDiags := local.Context(opReq)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(ctxDiags)
+		return 1
+	}
+
+	stateMgr, err := b.StateMgr(c.Workspace())
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(context.Background(), c.stateLockTimeout, c.Ui, c.Colorize())
+		if err := stateLocker.Lock(stateMgr, "state-upgrade"); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error locking state: %s", err))
+			return 1
+		}
+		defer stateLocker.Unlock(nil)
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+	if stateMgr.State() == nil {
+		c.Ui.Error(fmt.Sprintf(errStateNotFound))
+		return 1
+	}
+
+	upgradedState, results, upgradeDiags := ctx.UpgradeResourceState()
+	c.showDiagnostics(upgradeDiags)
+	if upgradeDiags.HasErrors() {
+		return 1
+	}
+
+	migrated := 0
+	for _, result := range results {
+		if !result.Upgraded() {
+			continue
+		}
+		migrated++
+		c.Ui.Output(fmt.Sprintf(
+			"- %s: schema version %d -> %d",
+			result.Addr, result.FromVersion, result.ToVersion,
+		))
+	}
+
+	if migrated == 0 {
+		c.Ui.Output("No resources required a state schema upgrade.")
+		return 0
+	}
+
+	if dryRun {
+		c.Ui.Output(fmt.Sprintf("\n%d resource(s) would be upgraded. No changes were persisted (-dry-run).", migrated))
+		return 0
+	}
+
+	if err := stateMgr.WriteState(upgradedState); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateRmPersist, err))
+		return 1
+	}
+	if err := stateMgr.PersistState(); err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateRmPersist, err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("\nSuccessfully upgraded the state schema of %d resource(s).", migrated))
+	return 0
+}
+
+func (c *StateUpgradeCommand) Help() string {
+	helpText := `
+Usage: terraform state upgrade [options]
+
+  Pre-runs any provider-defined state schema upgrades and reports which
+  resource instances were migrated.
+
+  Terraform normally applies these upgrades silently the next time a
+  resource is refreshed. This command instead runs them up front, against
+  every resource instance that is behind its provider's current schema
+  version, and reports the result before persisting the upgraded state.
+  Any data-loss warnings the provider produces during an upgrade are shown
+  as diagnostics.
+
+Options:
+
+  -dry-run            Report which resources would be upgraded without
+                       persisting the result.
+
+  -state=statefile    Path to a Terraform state file to use to look
+                       up Terraform-managed resources. By default, Terraform
+                       will consult the state of the currently-selected
+                       workspace.
+
+  -lock=true          Lock the state file when locking is supported.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateUpgradeCommand) Synopsis() string {
+	return "Pre-run and report provider state schema upgrades"
+}
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/backend"
+	"github.com/hashicorp/terraform/command/clistate"
+	"github.com/hashicorp/terraform/states/statemgr"
+	"github.com/hashicorp/terraform/tfdiags"
+	"github.com/mitchellh/cli"
+)
+
+// StateVerifyCommand is a Command implementation that checks the
+// consistency of the state without making any changes to infrastructure.
+type StateVerifyCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateVerifyCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var fixDeps bool
+	cmdFlags := c.Meta.defaultFlagSet("state verify")
+	cmdFlags.BoolVar(&fixDeps, "fix-deps", false, "remove dangling depends_on entries")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return cli.RunResultHelp
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The state verify command expects no arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting cwd: %s", err))
+		return 1
+	}
+
+	opReq := c.Operation(b)
+	opReq.AllowUnsetVariables = true
+	opReq.ConfigDir = cwd
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing config loader: %s", err))
+		return 1
+	}
+
+	ctx, _, ctxDiags := local.Context(opReq)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(ctxDiags)
+		return 1
+	}
+	schemas := ctx.Schemas()
+
+	stateMgr, err := b.StateMgr(c.Workspace())
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	if fixDeps {
+		stateLocker := clistate.NewLocker(context.Background(), c.stateLockTimeout, c.Ui, c.Colorize())
+		if err := stateLocker.Lock(stateMgr, "state-verify"); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error locking state: %s", err))
+			return 1
+		}
+		defer stateLocker.Unlock(nil)
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+	state := stateMgr.State()
+	if state == nil {
+		c.Ui.Error(fmt.Sprintf(errStateNotFound))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	if pm, ok := stateMgr.(statemgr.PersistentMeta); ok {
+		meta := pm.StateSnapshotMeta()
+		if meta.Lineage == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"State has no lineage",
+				"The state snapshot returned by the backend has no lineage value, so Terraform cannot verify that it is related to any prior snapshot.",
+			))
+		}
+	}
+
+	danglingDeps := 0
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			schema, currentVersion := schemas.ResourceTypeConfig(
+				rs.ProviderConfig.Provider, rs.Addr.Resource.Mode, rs.Addr.Resource.Type,
+			)
+			for key, is := range rs.Instances {
+				if is.Current == nil {
+					continue
+				}
+				instAddr := rs.Addr.Instance(key)
+
+				if schema == nil {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Warning,
+						"No cached schema for resource",
+						fmt.Sprintf("Terraform has no cached provider schema for %s, so its stored attributes could not be validated.", instAddr),
+					))
+				} else if is.Current.SchemaVersion != currentVersion {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Warning,
+						"Resource instance schema version mismatch",
+						fmt.Sprintf("%s is stored at schema version %d, but the current provider reports version %d. Run \"terraform state upgrade\" to migrate it.", instAddr, is.Current.SchemaVersion, currentVersion),
+					))
+				} else if _, err := is.Current.Decode(schema.ImpliedType()); err != nil {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Resource instance does not conform to its schema",
+						fmt.Sprintf("The stored attributes for %s do not conform to the current schema for its resource type: %s.", instAddr, err),
+					))
+				}
+
+				var kept []addrs.ConfigResource
+				for _, dep := range is.Current.Dependencies {
+					if len(state.Resources(dep)) == 0 {
+						danglingDeps++
+						diags = diags.Append(tfdiags.Sourceless(
+							tfdiags.Warning,
+							"Orphaned resource dependency",
+							fmt.Sprintf("%s depends on %s, which no longer exists in the state.", instAddr, dep),
+						))
+						continue
+					}
+					kept = append(kept, dep)
+				}
+				if fixDeps && len(kept) != len(is.Current.Dependencies) {
+					is.Current.Dependencies = kept
+				}
+			}
+		}
+	}
+
+	c.showDiagnostics(diags)
+
+	if fixDeps && danglingDeps > 0 {
+		if err := stateMgr.WriteState(state); err != nil {
+			c.Ui.Error(fmt.Sprintf(errStateRmPersist, err))
+			return 1
+		}
+		if err := stateMgr.PersistState(); err != nil {
+			c.Ui.Error(fmt.Sprintf(errStateRmPersist, err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("\nRemoved %d dangling depends_on entries.", danglingDeps))
+	}
+
+	if diags.HasErrors() {
+		return 1
+	}
+
+	c.Ui.Output("State verification complete.")
+	return 0
+}
+
+func (c *StateVerifyCommand) Help() string {
+	helpText := `
+Usage: terraform state verify [options]
+
+  Checks the consistency of the Terraform state without making any
+  changes to real infrastructure.
+
+  This command checks the lineage reported by the backend, validates every
+  resource instance's stored attributes against the cached provider schema,
+  and looks for depends_on entries that reference resources no longer
+  present in the state. Findings are reported as diagnostics.
+
+Options:
+
+  -fix-deps           Remove depends_on entries that reference resources
+                       no longer present in the state, persisting the
+                       result.
+
+  -state=statefile    Path to a Terraform state file to use to look
+                       up Terraform-managed resources. By default, Terraform
+                       will consult the state of the currently-selected
+                       workspace.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateVerifyCommand) Synopsis() string {
+	return "Verify the consistency of the state"
+}
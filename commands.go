@@ -251,6 +251,18 @@ func initCommands(config *cliconfig.Config, services *disco.Disco, providerSrc g
 			}, nil
 		},
 
+		"refs": func() (cli.Command, error) {
+			return &command.RefsCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"fix-providers": func() (cli.Command, error) {
+			return &command.FixProvidersCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"version": func() (cli.Command, error) {
 			return &command.VersionCommand{
 				Meta:              meta,
@@ -382,6 +394,24 @@ func initCommands(config *cliconfig.Config, services *disco.Disco, providerSrc g
 				},
 			}, nil
 		},
+
+		"state upgrade": func() (cli.Command, error) {
+			return &command.StateUpgradeCommand{
+				Meta: meta,
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
+
+		"state verify": func() (cli.Command, error) {
+			return &command.StateVerifyCommand{
+				Meta: meta,
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
 	}
 }
 
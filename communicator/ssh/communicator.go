@@ -773,42 +773,86 @@ func ConnectFunc(network, addr string) func() (net.Conn, error) {
 	}
 }
 
-// BastionConnectFunc is a convenience method for returning a function
-// that connects to a host over a bastion connection.
-func BastionConnectFunc(
-	bProto string,
-	bAddr string,
-	bConf *ssh.ClientConfig,
-	proto string,
-	addr string) func() (net.Conn, error) {
+// hop describes a single SSH connection to make en route to the final
+// target: either an intermediate jump host, identified by a non-nil conf,
+// or the final target itself, identified by a nil conf.
+type hop struct {
+	proto string
+	addr  string
+	conf  *ssh.ClientConfig
+}
+
+// BastionChainConnectFunc is a convenience method for returning a function
+// that connects to a host by dialing through zero or more intermediate
+// bastion hosts in order, forming a jump host chain. The final entry in
+// hops is the target host and must have a nil conf, since it is dialed
+// directly rather than authenticated as an SSH client.
+func BastionChainConnectFunc(hops []hop) func() (net.Conn, error) {
 	return func() (net.Conn, error) {
-		log.Printf("[DEBUG] Connecting to bastion: %s", bAddr)
-		bastion, err := ssh.Dial(bProto, bAddr, bConf)
+		if len(hops) == 0 {
+			return nil, errors.New("no hosts to connect to")
+		}
+		if len(hops) == 1 {
+			return ConnectFunc(hops[0].proto, hops[0].addr)()
+		}
+
+		var bastions []*ssh.Client
+		closeBastions := func() {
+			for i := len(bastions) - 1; i >= 0; i-- {
+				bastions[i].Close()
+			}
+		}
+
+		log.Printf("[DEBUG] Connecting to bastion: %s", hops[0].addr)
+		client, err := ssh.Dial(hops[0].proto, hops[0].addr, hops[0].conf)
 		if err != nil {
 			return nil, fmt.Errorf("Error connecting to bastion: %s", err)
 		}
+		bastions = append(bastions, client)
 
-		log.Printf("[DEBUG] Connecting via bastion (%s) to host: %s", bAddr, addr)
-		conn, err := bastion.Dial(proto, addr)
+		for _, h := range hops[1 : len(hops)-1] {
+			log.Printf("[DEBUG] Connecting via bastion chain to next hop: %s", h.addr)
+			conn, err := bastions[len(bastions)-1].Dial(h.proto, h.addr)
+			if err != nil {
+				closeBastions()
+				return nil, fmt.Errorf("Error connecting to bastion: %s", err)
+			}
+
+			ncc, chans, reqs, err := ssh.NewClientConn(conn, h.addr, h.conf)
+			if err != nil {
+				closeBastions()
+				return nil, fmt.Errorf("Error connecting to bastion: %s", err)
+			}
+			bastions = append(bastions, ssh.NewClient(ncc, chans, reqs))
+		}
+
+		target := hops[len(hops)-1]
+		log.Printf("[DEBUG] Connecting via bastion chain to host: %s", target.addr)
+		conn, err := bastions[len(bastions)-1].Dial(target.proto, target.addr)
 		if err != nil {
-			bastion.Close()
+			closeBastions()
 			return nil, err
 		}
 
-		// Wrap it up so we close both things properly
-		return &bastionConn{
-			Conn:    conn,
-			Bastion: bastion,
+		return &bastionChainConn{
+			Conn:     conn,
+			bastions: bastions,
 		}, nil
 	}
 }
 
-type bastionConn struct {
+// bastionChainConn wraps the final connection reached through a bastion
+// chain so that all of the intermediate SSH clients are closed along with
+// it.
+type bastionChainConn struct {
 	net.Conn
-	Bastion *ssh.Client
+	bastions []*ssh.Client
 }
 
-func (c *bastionConn) Close() error {
-	c.Conn.Close()
-	return c.Bastion.Close()
+func (c *bastionChainConn) Close() error {
+	err := c.Conn.Close()
+	for i := len(c.bastions) - 1; i >= 0; i-- {
+		c.bastions[i].Close()
+	}
+	return err
 }
@@ -61,7 +61,23 @@ type connectionInfo struct {
 	BastionHostKey     string `mapstructure:"bastion_host_key"`
 	BastionPort        int    `mapstructure:"bastion_port"`
 
+	// BastionHosts, if set, describes a chain of jump hosts to dial through
+	// in order, as a comma-separated list of "host" or "host:port" entries,
+	// with the last entry being closest to the final target. All hops
+	// authenticate using the Bastion* credentials above. This is an
+	// alternative to BastionHost for network policies that require more
+	// than one hop between the operator and the target.
+	BastionHosts string `mapstructure:"bastion_hosts"`
+
 	AgentIdentity string `mapstructure:"agent_identity"`
+
+	// Ciphers and KeyExchanges, if set, restrict the SSH connection (to
+	// both the target and any bastions) to the given comma-separated
+	// allow-lists, for network policies that forbid ciphers or key
+	// exchanges outside of a FIPS-approved set. An empty value leaves the
+	// underlying SSH library's default policy in place.
+	Ciphers      string `mapstructure:"ciphers"`
+	KeyExchanges string `mapstructure:"key_exchanges"`
 }
 
 // parseConnectionInfo is used to convert the ConnInfo of the InstanceState into
@@ -153,16 +169,21 @@ func prepareSSHConfig(connInfo *connectionInfo) (*sshConfig, error) {
 		return nil, err
 	}
 
+	ciphers := splitList(connInfo.Ciphers)
+	keyExchanges := splitList(connInfo.KeyExchanges)
+
 	host := fmt.Sprintf("%s:%d", connInfo.Host, connInfo.Port)
 
 	sshConf, err := buildSSHClientConfig(sshClientConfigOpts{
-		user:        connInfo.User,
-		host:        host,
-		privateKey:  connInfo.PrivateKey,
-		password:    connInfo.Password,
-		hostKey:     connInfo.HostKey,
-		certificate: connInfo.Certificate,
-		sshAgent:    sshAgent,
+		user:         connInfo.User,
+		host:         host,
+		privateKey:   connInfo.PrivateKey,
+		password:     connInfo.Password,
+		hostKey:      connInfo.HostKey,
+		certificate:  connInfo.Certificate,
+		sshAgent:     sshAgent,
+		ciphers:      ciphers,
+		keyExchanges: keyExchanges,
 	})
 	if err != nil {
 		return nil, err
@@ -170,24 +191,37 @@ func prepareSSHConfig(connInfo *connectionInfo) (*sshConfig, error) {
 
 	connectFunc := ConnectFunc("tcp", host)
 
-	var bastionConf *ssh.ClientConfig
-	if connInfo.BastionHost != "" {
-		bastionHost := fmt.Sprintf("%s:%d", connInfo.BastionHost, connInfo.BastionPort)
-
-		bastionConf, err = buildSSHClientConfig(sshClientConfigOpts{
-			user:        connInfo.BastionUser,
-			host:        bastionHost,
-			privateKey:  connInfo.BastionPrivateKey,
-			password:    connInfo.BastionPassword,
-			hostKey:     connInfo.HostKey,
-			certificate: connInfo.BastionCertificate,
-			sshAgent:    sshAgent,
-		})
-		if err != nil {
-			return nil, err
+	bastionHosts := bastionHopAddrs(connInfo)
+	if len(bastionHosts) > 0 {
+		// parseConnectionInfo only fills in the bastion defaults when
+		// BastionHost is set, so a BastionHosts chain falls back here.
+		orDefault := func(v, def string) string {
+			if v != "" {
+				return v
+			}
+			return def
+		}
+		var hops []hop
+		for _, bastionHost := range bastionHosts {
+			bastionConf, err := buildSSHClientConfig(sshClientConfigOpts{
+				user:         orDefault(connInfo.BastionUser, connInfo.User),
+				host:         bastionHost,
+				privateKey:   orDefault(connInfo.BastionPrivateKey, connInfo.PrivateKey),
+				password:     orDefault(connInfo.BastionPassword, connInfo.Password),
+				hostKey:      connInfo.BastionHostKey,
+				certificate:  orDefault(connInfo.BastionCertificate, connInfo.Certificate),
+				sshAgent:     sshAgent,
+				ciphers:      ciphers,
+				keyExchanges: keyExchanges,
+			})
+			if err != nil {
+				return nil, err
+			}
+			hops = append(hops, hop{proto: "tcp", addr: bastionHost, conf: bastionConf})
 		}
+		hops = append(hops, hop{proto: "tcp", addr: host})
 
-		connectFunc = BastionConnectFunc("tcp", bastionHost, bastionConf, "tcp", host)
+		connectFunc = BastionChainConnectFunc(hops)
 	}
 
 	config := &sshConfig{
@@ -198,14 +232,60 @@ func prepareSSHConfig(connInfo *connectionInfo) (*sshConfig, error) {
 	return config, nil
 }
 
+// bastionHopAddrs returns the ordered list of "host:port" jump host
+// addresses to dial through before reaching the final target, drawn from
+// BastionHosts if set (a comma-separated chain) or else from the single
+// BastionHost/BastionPort pair for backward compatibility.
+func bastionHopAddrs(connInfo *connectionInfo) []string {
+	if connInfo.BastionHosts != "" {
+		port := connInfo.BastionPort
+		if port == 0 {
+			port = connInfo.Port
+		}
+		var hosts []string
+		for _, entry := range splitList(connInfo.BastionHosts) {
+			if !strings.Contains(entry, ":") {
+				entry = fmt.Sprintf("%s:%d", entry, port)
+			}
+			hosts = append(hosts, entry)
+		}
+		return hosts
+	}
+
+	if connInfo.BastionHost != "" {
+		return []string{fmt.Sprintf("%s:%d", connInfo.BastionHost, connInfo.BastionPort)}
+	}
+
+	return nil
+}
+
+// splitList parses a comma-separated configuration value into a trimmed,
+// non-empty list of entries, returning nil if the value is empty.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 type sshClientConfigOpts struct {
-	privateKey  string
-	password    string
-	sshAgent    *sshAgent
-	certificate string
-	user        string
-	host        string
-	hostKey     string
+	privateKey   string
+	password     string
+	sshAgent     *sshAgent
+	certificate  string
+	user         string
+	host         string
+	hostKey      string
+	ciphers      []string
+	keyExchanges []string
 }
 
 func buildSSHClientConfig(opts sshClientConfigOpts) (*ssh.ClientConfig, error) {
@@ -242,6 +322,13 @@ func buildSSHClientConfig(opts sshClientConfigOpts) (*ssh.ClientConfig, error) {
 		User:            opts.user,
 	}
 
+	if len(opts.ciphers) > 0 {
+		conf.Config.Ciphers = opts.ciphers
+	}
+	if len(opts.keyExchanges) > 0 {
+		conf.Config.KeyExchanges = opts.keyExchanges
+	}
+
 	if opts.privateKey != "" {
 		if opts.certificate != "" {
 			log.Println("using client certificate for authentication")
@@ -131,3 +131,88 @@ func TestProvisioner_connInfoHostname(t *testing.T) {
 		t.Fatalf("bad %v", conf)
 	}
 }
+
+func TestBastionHopAddrs(t *testing.T) {
+	cases := []struct {
+		name string
+		conf *connectionInfo
+		want []string
+	}{
+		{
+			name: "none configured",
+			conf: &connectionInfo{},
+			want: nil,
+		},
+		{
+			name: "legacy single bastion host",
+			conf: &connectionInfo{BastionHost: "bastion.example.com", BastionPort: 22},
+			want: []string{"bastion.example.com:22"},
+		},
+		{
+			name: "chain with default ports",
+			conf: &connectionInfo{BastionHosts: "first.example.com, second.example.com", BastionPort: 2222},
+			want: []string{"first.example.com:2222", "second.example.com:2222"},
+		},
+		{
+			name: "chain with explicit per-hop ports",
+			conf: &connectionInfo{BastionHosts: "first.example.com:2200,second.example.com", BastionPort: 22},
+			want: []string{"first.example.com:2200", "second.example.com:22"},
+		},
+		{
+			name: "chain takes precedence over legacy single host",
+			conf: &connectionInfo{BastionHosts: "first.example.com", BastionHost: "ignored.example.com", BastionPort: 22},
+			want: []string{"first.example.com:22"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := bastionHopAddrs(tc.conf)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	if got := splitList(""); got != nil {
+		t.Fatalf("got %v, want nil", got)
+	}
+
+	got := splitList("aes128-gcm@openssh.com, aes256-gcm@openssh.com ,,chacha20-poly1305@openssh.com")
+	want := []string{"aes128-gcm@openssh.com", "aes256-gcm@openssh.com", "chacha20-poly1305@openssh.com"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBuildSSHClientConfig_ciphersAndKeyExchanges(t *testing.T) {
+	conf, err := buildSSHClientConfig(sshClientConfigOpts{
+		user:         "root",
+		host:         "example.com:22",
+		password:     "supersecret",
+		ciphers:      []string{"aes128-gcm@openssh.com"},
+		keyExchanges: []string{"curve25519-sha256"},
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if len(conf.Config.Ciphers) != 1 || conf.Config.Ciphers[0] != "aes128-gcm@openssh.com" {
+		t.Fatalf("bad ciphers: %v", conf.Config.Ciphers)
+	}
+	if len(conf.Config.KeyExchanges) != 1 || conf.Config.KeyExchanges[0] != "curve25519-sha256" {
+		t.Fatalf("bad key exchanges: %v", conf.Config.KeyExchanges)
+	}
+}
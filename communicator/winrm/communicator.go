@@ -56,10 +56,25 @@ func (c *Communicator) Connect(o terraform.UIOutput) error {
 		return nil
 	}
 
+	if c.connInfo.Kerberos {
+		return fmt.Errorf(
+			"Kerberos/SPNEGO authentication was requested (use_kerberos = true) but this " +
+				"build of Terraform does not include a Kerberos-capable WinRM transport. " +
+				"Use use_ntlm or basic auth instead, or build Terraform with a WinRM client " +
+				"library that implements SPNEGO.")
+	}
+
+	dial, err := proxyDialFunc(c.connInfo.Proxy)
+	if err != nil {
+		return err
+	}
+
 	params := winrm.DefaultParameters
 	params.Timeout = formatDuration(c.Timeout())
 	if c.connInfo.NTLM == true {
-		params.TransportDecorator = func() winrm.Transporter { return &winrm.ClientNTLM{} }
+		params.TransportDecorator = func() winrm.Transporter { return winrm.NewClientNTLMWithDial(dial) }
+	} else if dial != nil {
+		params.TransportDecorator = func() winrm.Transporter { return winrm.NewClientAuthRequestWithDial(dial) }
 	}
 
 	client, err := winrm.NewClientWithParameters(
@@ -78,7 +93,8 @@ func (c *Communicator) Connect(o terraform.UIOutput) error {
 				"  HTTPS: %t\n"+
 				"  Insecure: %t\n"+
 				"  NTLM: %t\n"+
-				"  CACert: %t",
+				"  CACert: %t\n"+
+				"  Proxy: %t",
 			c.connInfo.Host,
 			c.connInfo.Port,
 			c.connInfo.User,
@@ -87,6 +103,7 @@ func (c *Communicator) Connect(o terraform.UIOutput) error {
 			c.connInfo.Insecure,
 			c.connInfo.NTLM,
 			c.connInfo.CACert != "",
+			c.connInfo.Proxy != "",
 		))
 	}
 
@@ -176,8 +193,21 @@ func (c *Communicator) UploadDir(dst string, src string) error {
 }
 
 func (c *Communicator) newCopyClient() (*winrmcp.Winrmcp, error) {
+	if c.connInfo.Kerberos {
+		return nil, fmt.Errorf(
+			"Kerberos/SPNEGO authentication was requested (use_kerberos = true) but this " +
+				"build of Terraform does not include a Kerberos-capable WinRM transport. " +
+				"Use use_ntlm or basic auth instead, or build Terraform with a WinRM client " +
+				"library that implements SPNEGO.")
+	}
+
 	addr := fmt.Sprintf("%s:%d", c.endpoint.Host, c.endpoint.Port)
 
+	dial, err := proxyDialFunc(c.connInfo.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
 	config := winrmcp.Config{
 		Auth: winrmcp.Auth{
 			User:     c.connInfo.User,
@@ -190,7 +220,9 @@ func (c *Communicator) newCopyClient() (*winrmcp.Winrmcp, error) {
 	}
 
 	if c.connInfo.NTLM == true {
-		config.TransportDecorator = func() winrm.Transporter { return &winrm.ClientNTLM{} }
+		config.TransportDecorator = func() winrm.Transporter { return winrm.NewClientNTLMWithDial(dial) }
+	} else if dial != nil {
+		config.TransportDecorator = func() winrm.Transporter { return winrm.NewClientAuthRequestWithDial(dial) }
 	}
 
 	if c.connInfo.CACert != "" {
@@ -81,6 +81,29 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestConnect_kerberosRejected(t *testing.T) {
+	r := &terraform.InstanceState{
+		Ephemeral: terraform.EphemeralState{
+			ConnInfo: map[string]string{
+				"type":         "winrm",
+				"user":         "user",
+				"password":     "pass",
+				"host":         "example.com",
+				"use_kerberos": "true",
+			},
+		},
+	}
+
+	c, err := New(r)
+	if err != nil {
+		t.Fatalf("error creating communicator: %s", err)
+	}
+
+	if err := c.Connect(nil); err == nil {
+		t.Fatal("expected Connect to reject use_kerberos, but it did not")
+	}
+}
+
 func TestUpload(t *testing.T) {
 	wrm := newMockWinRMServer(t)
 	defer wrm.Close()
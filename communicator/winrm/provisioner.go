@@ -45,6 +45,17 @@ type connectionInfo struct {
 	Timeout    string
 	ScriptPath string        `mapstructure:"script_path"`
 	TimeoutVal time.Duration `mapstructure:"-"`
+
+	// Kerberos requests SPNEGO/Kerberos authentication instead of NTLM
+	// or basic auth. This build of Terraform doesn't include a
+	// Kerberos-capable WinRM transport, so setting this is always
+	// rejected; see communicator.go.
+	Kerberos bool `mapstructure:"use_kerberos"`
+
+	// Proxy is the URL (e.g. "http://proxy.example.com:8080") of an
+	// HTTP/HTTPS proxy to tunnel the WinRM connection through, for
+	// networks where the target is only reachable via a jump proxy.
+	Proxy string `mapstructure:"proxy"`
 }
 
 // parseConnectionInfo is used to convert the ConnInfo of the InstanceState into
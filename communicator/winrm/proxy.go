@@ -0,0 +1,64 @@
+package winrm
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// proxyDialFunc returns a dial function that establishes a TCP connection
+// to addr by tunneling through the given HTTP/HTTPS proxy via the CONNECT
+// method, for use as a custom Dial with the winrm and winrmcp clients.
+// It returns nil if proxyURL is empty, in which case the caller should
+// fall back to a direct dial.
+func proxyDialFunc(proxyURL string) (func(network, addr string) (net.Conn, error), error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid winrm proxy URL %q: %s", proxyURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid winrm proxy URL %q: missing host", proxyURL)
+	}
+
+	return func(network, addr string) (net.Conn, error) {
+		conn, err := net.Dial(network, u.Host)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to proxy %s: %s", u.Host, err)
+		}
+
+		connectReq := &http.Request{
+			Method: "CONNECT",
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: make(http.Header),
+		}
+		if u.User != nil {
+			if password, ok := u.User.Password(); ok {
+				connectReq.SetBasicAuth(u.User.Username(), password)
+			}
+		}
+		if err := connectReq.Write(conn); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error writing CONNECT request to proxy %s: %s", u.Host, err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error reading CONNECT response from proxy %s: %s", u.Host, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			conn.Close()
+			return nil, fmt.Errorf("proxy %s refused CONNECT to %s: %s", u.Host, addr, resp.Status)
+		}
+
+		return conn, nil
+	}, nil
+}
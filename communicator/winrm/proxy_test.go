@@ -0,0 +1,100 @@
+package winrm
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestProxyDialFunc_empty(t *testing.T) {
+	dial, err := proxyDialFunc("")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if dial != nil {
+		t.Fatal("expected a nil dial func when no proxy is configured")
+	}
+}
+
+func TestProxyDialFunc_invalidURL(t *testing.T) {
+	if _, err := proxyDialFunc("http://%zz"); err == nil {
+		t.Fatal("expected an error for an unparseable proxy URL")
+	}
+}
+
+func TestProxyDialFunc_missingHost(t *testing.T) {
+	if _, err := proxyDialFunc("http:///path"); err == nil {
+		t.Fatal("expected an error for a proxy URL with no host")
+	}
+}
+
+// TestProxyDialFunc_connect spins up a minimal CONNECT proxy and verifies
+// that the dial func returned by proxyDialFunc tunnels through it
+// successfully.
+func TestProxyDialFunc_connect(t *testing.T) {
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		conn, err := target.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("hello from target"))
+	}()
+
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+	go func() {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil || req.Method != "CONNECT" {
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+		upstream, err := net.Dial("tcp", target.Addr().String())
+		if err != nil {
+			return
+		}
+		defer upstream.Close()
+
+		buf := make([]byte, 32)
+		n, _ := upstream.Read(buf)
+		conn.Write(buf[:n])
+	}()
+
+	dial, err := proxyDialFunc("http://" + proxy.Addr().String())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if dial == nil {
+		t.Fatal("expected a non-nil dial func")
+	}
+
+	conn, err := dial("tcp", target.Addr().String())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer conn.Close()
+
+	buf := make([]byte, len("hello from target"))
+	if _, err := conn.Read(buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if string(buf) != "hello from target" {
+		t.Fatalf("wrong content: %q", buf)
+	}
+}
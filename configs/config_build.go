@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"fmt"
 	"sort"
 
 	version "github.com/hashicorp/go-version"
@@ -64,6 +65,19 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			continue
 		}
 
+		if mod.Deprecated != nil {
+			detail := fmt.Sprintf("Module %q is deprecated: %s", call.SourceAddr, mod.Deprecated.Message)
+			if mod.Deprecated.ReplacementSource != "" {
+				detail = fmt.Sprintf("%s\n\nReplace it with %q.", detail, mod.Deprecated.ReplacementSource)
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Deprecated module",
+				Detail:   detail,
+				Subject:  &call.DeclRange,
+			})
+		}
+
 		child := &Config{
 			Parent:          parent,
 			Root:            parent.Root,
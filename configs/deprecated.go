@@ -0,0 +1,56 @@
+package configs
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Deprecated represents a "deprecated" block inside a "terraform" block in a
+// module, used by module authors to warn callers that the module (or this
+// particular version of it) should no longer be used.
+type Deprecated struct {
+	// Message is a human-readable explanation shown to anyone who calls
+	// this module.
+	Message string
+
+	// ReplacementSource is an optional module source address that callers
+	// should migrate to instead.
+	ReplacementSource string
+
+	DeclRange hcl.Range
+}
+
+func decodeDeprecatedBlock(block *hcl.Block) (*Deprecated, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := &Deprecated{
+		DeclRange: block.DefRange,
+	}
+
+	content, contentDiags := block.Body.Content(deprecatedBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	message, msgDiags := decodeOptionalStringAttr(content, "message")
+	diags = append(diags, msgDiags...)
+	ret.Message = message
+
+	replacementSource, srcDiags := decodeOptionalStringAttr(content, "replacement_source")
+	diags = append(diags, srcDiags...)
+	ret.ReplacementSource = replacementSource
+
+	if ret.Message == "" {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing \"message\" argument",
+			Detail:   "A deprecated block must include a \"message\" argument explaining why the module is deprecated.",
+			Subject:  &block.DefRange,
+		})
+	}
+
+	return ret, diags
+}
+
+var deprecatedBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "message", Required: true},
+		{Name: "replacement_source"},
+	},
+}
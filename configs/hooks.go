@@ -0,0 +1,49 @@
+package configs
+
+import (
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Hooks represents a "hooks" block inside a "terraform" block in a module,
+// used to run a local command after an apply completes so that notification
+// or cache-invalidation glue can live in the configuration rather than in
+// an external wrapper script.
+type Hooks struct {
+	// OnApplySuccess, if set, is a command that Terraform runs after a
+	// successful apply, with the apply's machine-readable result available
+	// on the command's standard input.
+	OnApplySuccess string
+
+	// OnApplyFailure, if set, is the equivalent of OnApplySuccess for a
+	// failed apply.
+	OnApplyFailure string
+
+	DeclRange hcl.Range
+}
+
+func decodeHooksBlock(block *hcl.Block) (*Hooks, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := &Hooks{
+		DeclRange: block.DefRange,
+	}
+
+	content, contentDiags := block.Body.Content(hooksBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	onApplySuccess, successDiags := decodeOptionalStringAttr(content, "on_apply_success")
+	diags = append(diags, successDiags...)
+	ret.OnApplySuccess = onApplySuccess
+
+	onApplyFailure, failureDiags := decodeOptionalStringAttr(content, "on_apply_failure")
+	diags = append(diags, failureDiags...)
+	ret.OnApplyFailure = onApplyFailure
+
+	return ret, diags
+}
+
+var hooksBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "on_apply_success"},
+		{Name: "on_apply_failure"},
+	},
+}
@@ -33,6 +33,8 @@ type Module struct {
 	ProviderRequirements *RequiredProviders
 	ProviderLocalNames   map[addrs.Provider]string
 	ProviderMetas        map[addrs.Provider]*ProviderMeta
+	Deprecated           *Deprecated
+	Hooks                *Hooks
 
 	Variables map[string]*Variable
 	Locals    map[string]*Local
@@ -64,6 +66,8 @@ type File struct {
 	ProviderConfigs   []*Provider
 	ProviderMetas     []*ProviderMeta
 	RequiredProviders []*RequiredProviders
+	Deprecated        []*Deprecated
+	Hooks             []*Hooks
 
 	Variables []*Variable
 	Locals    []*Local
@@ -188,6 +192,32 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		m.Backend = b
 	}
 
+	for _, d := range file.Deprecated {
+		if m.Deprecated != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate deprecated configuration",
+				Detail:   fmt.Sprintf("A module may have only one deprecated configuration. The deprecation was previously configured at %s.", m.Deprecated.DeclRange),
+				Subject:  &d.DeclRange,
+			})
+			continue
+		}
+		m.Deprecated = d
+	}
+
+	for _, h := range file.Hooks {
+		if m.Hooks != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate hooks configuration",
+				Detail:   fmt.Sprintf("A module may have only one hooks configuration. The hooks block was previously configured at %s.", m.Hooks.DeclRange),
+				Subject:  &h.DeclRange,
+			})
+			continue
+		}
+		m.Hooks = h
+	}
+
 	for _, pc := range file.ProviderConfigs {
 		key := pc.moduleUniqueKey()
 		if existing, exists := m.ProviderConfigs[key]; exists {
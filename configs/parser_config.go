@@ -84,6 +84,20 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 						file.ProviderMetas = append(file.ProviderMetas, providerCfg)
 					}
 
+				case "deprecated":
+					deprecated, cfgDiags := decodeDeprecatedBlock(innerBlock)
+					diags = append(diags, cfgDiags...)
+					if deprecated != nil {
+						file.Deprecated = append(file.Deprecated, deprecated)
+					}
+
+				case "hooks":
+					hooks, cfgDiags := decodeHooksBlock(innerBlock)
+					diags = append(diags, cfgDiags...)
+					if hooks != nil {
+						file.Hooks = append(file.Hooks, hooks)
+					}
+
 				default:
 					// Should never happen because the above cases should be exhaustive
 					// for all block type names in our schema.
@@ -257,6 +271,12 @@ var terraformBlockSchema = &hcl.BodySchema{
 			Type:       "provider_meta",
 			LabelNames: []string{"provider"},
 		},
+		{
+			Type: "deprecated",
+		},
+		{
+			Type: "hooks",
+		},
 	},
 }
 
@@ -1,8 +1,12 @@
 package configs
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 // exprIsNativeQuotedString determines whether the given expression looks like
@@ -61,3 +65,40 @@ func schemaWithDynamic(schema *hcl.BodySchema) *hcl.BodySchema {
 
 	return ret
 }
+
+// decodeOptionalStringAttr evaluates the named attribute in content, if
+// present, and returns its value converted to a string.
+//
+// This is shared by simple decoders for blocks whose attributes are all
+// plain strings, such as "deprecated" and "hooks". Whether an attribute is
+// required is left to the block's schema; an absent attribute decodes as "".
+func decodeOptionalStringAttr(content *hcl.BodyContent, name string) (string, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attr, exists := content.Attributes[name]
+	if !exists {
+		return "", diags
+	}
+
+	val, valDiags := attr.Expr.Value(nil)
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return "", diags
+	}
+
+	val, err := convert.Convert(val, cty.String)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %q argument", name),
+			Detail:   fmt.Sprintf("A string value is required for %q: %s.", name, err),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return "", diags
+	}
+	if val.IsNull() {
+		return "", diags
+	}
+
+	return val.AsString(), diags
+}
@@ -0,0 +1,148 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/states"
+	"github.com/hashicorp/terraform/tfdiags"
+)
+
+// StateUpgradeResult describes the outcome of upgrading a single resource
+// instance's state to the schema version reported by its current provider,
+// as performed by Context.UpgradeResourceState.
+type StateUpgradeResult struct {
+	Addr addrs.AbsResourceInstance
+
+	// FromVersion and ToVersion are the schema versions the instance was
+	// upgraded from and to. Context.UpgradeResourceState only reports
+	// objects whose stored version differs from the current one, so they
+	// are never equal in its results.
+	FromVersion uint64
+	ToVersion   uint64
+
+	// Diagnostics collects any errors or data-loss warnings the provider
+	// returned while performing the upgrade for this instance.
+	Diagnostics tfdiags.Diagnostics
+}
+
+// Upgraded returns true if this result represents an actual schema version
+// change, i.e. FromVersion and ToVersion differ.
+func (r StateUpgradeResult) Upgraded() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// UpgradeResourceState walks every resource instance object in the
+// receiver's state, including both the current object and any deposed
+// objects left behind by an interrupted create_before_destroy replacement,
+// and for any whose schema version is behind the version reported by its
+// current provider, runs the provider-defined upgrade logic that would
+// otherwise happen implicitly during the next refresh.
+//
+// Unlike the implicit upgrade performed during refresh, this method performs
+// the upgrade in isolation, returning a new state along with a report
+// describing what was migrated. It does not mutate the receiver's state.
+func (c *Context) UpgradeResourceState() (*states.State, []StateUpgradeResult, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var results []StateUpgradeResult
+
+	if c.state == nil {
+		return nil, nil, diags
+	}
+
+	newState := c.state.DeepCopy()
+	liveProviders := map[addrs.Provider]providers.Interface{}
+	defer func() {
+		for _, p := range liveProviders {
+			p.Close()
+		}
+	}()
+
+	for _, ms := range newState.Modules {
+		for _, rs := range ms.Resources {
+			providerAddr := rs.ProviderConfig.Provider
+			schema := c.schemas.ProviderSchema(providerAddr)
+			if schema == nil {
+				continue
+			}
+			currentSchema, currentVersion := schema.SchemaForResourceType(rs.Addr.Resource.Mode, rs.Addr.Resource.Type)
+			if currentSchema == nil {
+				continue
+			}
+
+			for key, is := range rs.Instances {
+				instAddr := rs.Addr.Instance(key)
+
+				getProvider := func() (providers.Interface, error) {
+					provider, ok := liveProviders[providerAddr]
+					if ok {
+						return provider, nil
+					}
+					provider, err := c.components.ResourceProvider(providerAddr)
+					if err != nil {
+						return nil, err
+					}
+					liveProviders[providerAddr] = provider
+					return provider, nil
+				}
+
+				if is.Current != nil && is.Current.SchemaVersion != currentVersion {
+					provider, err := getProvider()
+					if err != nil {
+						diags = diags.Append(tfdiags.Sourceless(
+							tfdiags.Error,
+							"Failed to instantiate provider",
+							fmt.Sprintf("Could not instantiate provider %q to upgrade the state for %s: %s.", providerAddr, instAddr, err),
+						))
+						continue
+					}
+
+					fromVersion := is.Current.SchemaVersion
+					upgraded, upgradeDiags := UpgradeResourceState(instAddr, provider, is.Current, currentSchema, currentVersion)
+					results = append(results, StateUpgradeResult{
+						Addr:        instAddr,
+						FromVersion: fromVersion,
+						ToVersion:   currentVersion,
+						Diagnostics: upgradeDiags,
+					})
+					diags = diags.Append(upgradeDiags)
+					if !upgradeDiags.HasErrors() {
+						is.Current = upgraded
+					}
+				}
+
+				for deposedKey, deposed := range is.Deposed {
+					if deposed == nil || deposed.SchemaVersion == currentVersion {
+						continue
+					}
+
+					provider, err := getProvider()
+					if err != nil {
+						diags = diags.Append(tfdiags.Sourceless(
+							tfdiags.Error,
+							"Failed to instantiate provider",
+							fmt.Sprintf("Could not instantiate provider %q to upgrade the deposed state for %s: %s.", providerAddr, instAddr, err),
+						))
+						continue
+					}
+
+					fromVersion := deposed.SchemaVersion
+					upgraded, upgradeDiags := UpgradeResourceState(instAddr, provider, deposed, currentSchema, currentVersion)
+					results = append(results, StateUpgradeResult{
+						Addr:        instAddr,
+						FromVersion: fromVersion,
+						ToVersion:   currentVersion,
+						Diagnostics: upgradeDiags,
+					})
+					diags = diags.Append(upgradeDiags)
+					if !upgradeDiags.HasErrors() {
+						is.Deposed[deposedKey] = upgraded
+					}
+				}
+			}
+		}
+	}
+
+	return newState, results, diags
+}
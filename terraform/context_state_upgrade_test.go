@@ -0,0 +1,196 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/addrs"
+	"github.com/hashicorp/terraform/providers"
+	"github.com/hashicorp/terraform/states"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestContext2UpgradeResourceState(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "refresh-basic")
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("aws_instance.web").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:        states.ObjectReady,
+			AttrsJSON:     []byte(`{"id":"foo","foo":"bar"}`),
+			SchemaVersion: 0,
+		},
+		mustProviderConfig(`provider["registry.terraform.io/hashicorp/aws"]`),
+	)
+
+	p.GetSchemaReturn.ResourceTypeSchemaVersions = map[string]uint64{
+		"aws_instance": 1,
+	}
+
+	schema := p.GetSchemaReturn.ResourceTypes["aws_instance"]
+	ty := schema.ImpliedType()
+
+	attrs := map[string]cty.Value{}
+	for name, atype := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(atype)
+	}
+	attrs["id"] = cty.StringVal("foo")
+	attrs["foo"] = cty.StringVal("bar-upgraded")
+
+	p.UpgradeResourceStateResponse = providers.UpgradeResourceStateResponse{
+		UpgradedState: cty.ObjectVal(attrs),
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Config: m,
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	newState, results, diags := ctx.UpgradeResourceState()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	if !p.UpgradeResourceStateCalled {
+		t.Fatal("expected the provider's UpgradeResourceState to be called")
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %#v", len(results), results)
+	}
+	result := results[0]
+	if !result.Upgraded() {
+		t.Fatalf("expected result to report an upgrade: %#v", result)
+	}
+	if result.FromVersion != 0 || result.ToVersion != 1 {
+		t.Fatalf("wrong versions: %#v", result)
+	}
+
+	is := newState.RootModule().Resources["aws_instance.web"].Instances[addrs.NoKey]
+	val, err := is.Current.Decode(ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := val.Value.GetAttr("foo").AsString()
+	if got != "bar-upgraded" {
+		t.Fatalf("wrong upgraded value: got %q", got)
+	}
+
+	// The receiver's own state must not have been mutated.
+	origInst := state.RootModule().Resources["aws_instance.web"].Instances[addrs.NoKey]
+	if origInst.Current.SchemaVersion != 0 {
+		t.Fatalf("receiver state was mutated: %#v", origInst.Current)
+	}
+}
+
+func TestContext2UpgradeResourceState_deposed(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "refresh-basic")
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceDeposed(
+		mustResourceInstanceAddr("aws_instance.web").Resource,
+		states.NewDeposedKey(),
+		&states.ResourceInstanceObjectSrc{
+			Status:        states.ObjectReady,
+			AttrsJSON:     []byte(`{"id":"foo","foo":"bar"}`),
+			SchemaVersion: 0,
+		},
+		mustProviderConfig(`provider["registry.terraform.io/hashicorp/aws"]`),
+	)
+
+	p.GetSchemaReturn.ResourceTypeSchemaVersions = map[string]uint64{
+		"aws_instance": 1,
+	}
+
+	schema := p.GetSchemaReturn.ResourceTypes["aws_instance"]
+	ty := schema.ImpliedType()
+
+	attrs := map[string]cty.Value{}
+	for name, atype := range ty.AttributeTypes() {
+		attrs[name] = cty.NullVal(atype)
+	}
+	attrs["id"] = cty.StringVal("foo")
+	attrs["foo"] = cty.StringVal("bar-upgraded")
+
+	p.UpgradeResourceStateResponse = providers.UpgradeResourceStateResponse{
+		UpgradedState: cty.ObjectVal(attrs),
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Config: m,
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	newState, results, diags := ctx.UpgradeResourceState()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	if !p.UpgradeResourceStateCalled {
+		t.Fatal("expected the provider's UpgradeResourceState to be called")
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %#v", len(results), results)
+	}
+	if !results[0].Upgraded() {
+		t.Fatalf("expected result to report an upgrade: %#v", results[0])
+	}
+
+	is := newState.RootModule().Resources["aws_instance.web"].Instances[addrs.NoKey]
+	if len(is.Deposed) != 1 {
+		t.Fatalf("expected 1 deposed object, got %d", len(is.Deposed))
+	}
+	for _, deposed := range is.Deposed {
+		val, err := deposed.Decode(ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := val.Value.GetAttr("foo").AsString()
+		if got != "bar-upgraded" {
+			t.Fatalf("wrong upgraded value: got %q", got)
+		}
+	}
+}
+
+func TestContext2UpgradeResourceState_noop(t *testing.T) {
+	p := testProvider("aws")
+	m := testModule(t, "refresh-basic")
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("aws_instance.web").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"foo","foo":"bar"}`),
+		},
+		mustProviderConfig(`provider["registry.terraform.io/hashicorp/aws"]`),
+	)
+
+	ctx := testContext2(t, &ContextOpts{
+		Config: m,
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+		State: state,
+	})
+
+	_, results, diags := ctx.UpgradeResourceState()
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no upgrades since schema version already matches, got: %#v", results)
+	}
+}
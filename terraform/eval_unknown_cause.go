@@ -0,0 +1,72 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/lang"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// describeUnknownDependencies inspects the given expression for references
+// to other objects in the configuration and produces a human-readable
+// sentence listing them, for use as a supplement to diagnostic messages
+// about count/for_each values that can't be determined until apply.
+//
+// This can't tell us which of the references is actually responsible for
+// the unknown value -- that would require tracing the unknown value's
+// provenance through the evaluated result, which we don't currently keep
+// track of -- but giving the user the full set of candidate resource
+// addresses and attribute paths is usually enough for them to narrow down
+// which upstream attribute they need to -target.
+//
+// The result is an empty string if expr contains no references at all, in
+// which case the caller should omit it from its diagnostic message.
+func describeUnknownDependencies(expr hcl.Expression) string {
+	if expr == nil {
+		return ""
+	}
+
+	refs, _ := lang.ReferencesInExpr(expr)
+	if len(refs) == 0 {
+		return ""
+	}
+
+	names := make(map[string]bool)
+	for _, ref := range refs {
+		name := ref.Subject.String() + traversalSuffix(ref.Remaining)
+		names[name] = true
+	}
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return fmt.Sprintf(" Terraform traced the unknown value to one or more of the following: %s.", strings.Join(list, ", "))
+}
+
+// traversalSuffix renders a relative attribute traversal, such as the
+// Remaining field of an addrs.Reference, back into dotted/indexed source
+// syntax so it can be appended to the address it's relative to.
+func traversalSuffix(traversal hcl.Traversal) string {
+	var buf strings.Builder
+	for _, step := range traversal {
+		switch ts := step.(type) {
+		case hcl.TraverseAttr:
+			buf.WriteString(".")
+			buf.WriteString(ts.Name)
+		case hcl.TraverseIndex:
+			switch {
+			case ts.Key.Type() == cty.String:
+				fmt.Fprintf(&buf, "[%q]", ts.Key.AsString())
+			case ts.Key.Type() == cty.Number:
+				fmt.Fprintf(&buf, "[%s]", ts.Key.AsBigFloat().String())
+			}
+		}
+	}
+	return buf.String()
+}
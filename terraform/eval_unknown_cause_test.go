@@ -0,0 +1,52 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestDescribeUnknownDependencies(t *testing.T) {
+	tests := []struct {
+		Expr string
+		Want string
+	}{
+		{
+			`"static"`,
+			``,
+		},
+		{
+			`"${aws_instance.foo.id}"`,
+			` Terraform traced the unknown value to one or more of the following: aws_instance.foo.id.`,
+		},
+		{
+			`"${aws_instance.foo.id}-${aws_instance.bar.tags["env"]}"`,
+			` Terraform traced the unknown value to one or more of the following: aws_instance.bar.tags["env"], aws_instance.foo.id.`,
+		},
+		{
+			`"${aws_instance.foo[0].id}"`,
+			` Terraform traced the unknown value to one or more of the following: aws_instance.foo[0].id.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Expr, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseTemplate([]byte(test.Expr), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("parse error: %s", diags.Error())
+			}
+
+			got := describeUnknownDependencies(expr)
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestDescribeUnknownDependencies_nilExpr(t *testing.T) {
+	if got := describeUnknownDependencies(nil); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+}